@@ -0,0 +1,116 @@
+package pool
+
+import (
+	"sync"
+	"time"
+)
+
+// allocLimiter is a token bucket capping how fast the pool allocates new objects,
+// so a caller doing far more Gets than Puts can't make the pool allocate without bound.
+// The bucket holds up to one second worth of allocations and refills continuously.
+//
+// A nil limiter is valid and never limits, so pools without a max allocation rate pay nothing for it.
+type allocLimiter struct {
+	mu sync.Mutex
+
+	// rate is the number of allocations allowed per second, and also the bucket size.
+	rate float64
+
+	// tokens is the number of allocations currently allowed, it goes negative while
+	// blocked allocations wait for tokens they already reserved.
+	tokens float64
+
+	// last is when tokens was last refilled.
+	last time.Time
+
+	// block makes allocations wait for a token instead of failing with ErrAllocRateExceeded.
+	block bool
+}
+
+// newAllocLimiter returns a limiter for the max allocation rate in config, or nil when there's none.
+func newAllocLimiter[T any](config *PoolConfig[T]) *allocLimiter {
+	if config.maxAllocRate <= 0 {
+		return nil
+	}
+
+	rate := float64(config.maxAllocRate)
+	return &allocLimiter{
+		rate:   rate,
+		tokens: rate,
+		last:   time.Now(),
+		block:  config.blockOnAllocRate,
+	}
+}
+
+// refill adds the tokens accumulated since the last refill, l.mu must be held.
+func (l *allocLimiter) refill() {
+	now := time.Now()
+	l.tokens = min(l.rate, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+}
+
+// take takes up to n tokens without blocking and returns how many it got, so that batch allocations
+// made while holding the pool's lock shrink to the allowed rate instead of waiting for it.
+func (l *allocLimiter) take(n int) int {
+	if l == nil || n <= 0 {
+		return n
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill()
+	granted := min(n, int(l.tokens))
+	l.tokens -= float64(granted)
+
+	return granted
+}
+
+// waitForToken is called, without holding the pool's lock, after an allocation failed with ErrAllocRateExceeded.
+// When blocking is configured it sleeps until a token is available and returns nil so the caller can try again,
+// otherwise it returns ErrAllocRateExceeded right away.
+func (l *allocLimiter) waitForToken() error {
+	if l == nil || !l.block {
+		return ErrAllocRateExceeded
+	}
+
+	l.mu.Lock()
+	l.refill()
+	delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+
+	return nil
+}
+
+// wait takes a token for one allocation made without holding the pool's lock. When the bucket is empty
+// it either blocks until the token is available or returns ErrAllocRateExceeded, depending on the configuration.
+func (l *allocLimiter) wait() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	l.refill()
+
+	if l.tokens >= 1 {
+		l.tokens--
+		l.mu.Unlock()
+		return nil
+	}
+
+	if !l.block {
+		l.mu.Unlock()
+		return ErrAllocRateExceeded
+	}
+
+	delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	l.tokens--
+	l.mu.Unlock()
+
+	time.Sleep(delay)
+	return nil
+}
@@ -184,6 +184,32 @@ type PoolConfigBuilder[T any] interface {
 	SetRingBufferReadTimeout(d time.Duration) PoolConfigBuilder[T]
 	// SetRingBufferWriteTimeout sets the write timeout for the ring buffer
 	SetRingBufferWriteTimeout(d time.Duration) PoolConfigBuilder[T]
+	// SetMaxObjectAge sets how long an object may sit in the pool before it's discarded on Get (0 disables)
+	SetMaxObjectAge(d time.Duration) PoolConfigBuilder[T]
+	// SetResetOnGet sets a hook that runs on every object returned by Get (nil disables)
+	SetResetOnGet(reset func(T)) PoolConfigBuilder[T]
+	// SetSizeOf sets a function reporting the approximate size of an object in bytes (nil disables)
+	SetSizeOf(sizeOf func(T) int) PoolConfigBuilder[T]
+	// SetValidator sets a check run on Put, objects failing it are discarded instead of reused (nil disables)
+	SetValidator(validator func(T) bool) PoolConfigBuilder[T]
+	// SetMaxInFlight sets how many objects can be checked out at once before Get blocks (0 disables)
+	SetMaxInFlight(n int) PoolConfigBuilder[T]
+	// SetMaxAllocRate caps allocations per second, blocking or failing Get when exceeded (0 disables)
+	SetMaxAllocRate(perSecond int, block bool) PoolConfigBuilder[T]
+	// SetReuseOnAllocFailure makes Get serve an existing (possibly stale) object when allocation fails
+	SetReuseOnAllocFailure(enable bool) PoolConfigBuilder[T]
+	// SetDetectDoubleRelease makes Put reject objects that aren't checked out, a debug aid (disabled by default)
+	SetDetectDoubleRelease(enable bool) PoolConfigBuilder[T]
+	// SetLockFree backs the pool with a sync.Pool for throughput, making its stats approximate
+	SetLockFree(enable bool) PoolConfigBuilder[T]
+	// SetCopyOnGet makes Get return a copy of the pooled object, keeping the original pristine
+	SetCopyOnGet(copyFn func(T) T) PoolConfigBuilder[T]
+	// SetAsyncClean makes Put hand returned objects to a background cleaner through a bounded queue
+	SetAsyncClean(queueSize int) PoolConfigBuilder[T]
+	// SetVersioning enables Pool.Invalidate, retiring every object created before the call
+	SetVersioning(enable bool) PoolConfigBuilder[T]
+	// SetRecordLatency records Get latencies into a histogram reported by GetPoolStatsSnapshot
+	SetRecordLatency(enable bool) PoolConfigBuilder[T]
 	// Build creates and returns a new PoolConfig with the specified settings
 	Build() (*PoolConfig[T], error)
 }
@@ -0,0 +1,131 @@
+package pool
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// BucketedPool serves variable-size objects (typically buffers) from a set of pools, one per size class.
+// Get(size) is served by the smallest size class that fits, so small requests don't end up holding
+// objects sized for the largest ones, and Put returns the object to the pool it came from.
+type BucketedPool[T any] struct {
+	// sizes holds the size classes in ascending order, sizes[i] is served by pools[i].
+	sizes []int
+	pools []*Pool[T]
+
+	mu sync.Mutex
+
+	// origins maps every checked out object to the index of the pool it came from,
+	// so Put sends it back there even if the object grew while it was in use.
+	origins map[any]int
+}
+
+// NewBucketedPool creates one pool per size class, all sharing the given configuration.
+//
+// The allocator function receives the size class being allocated for and returns a pointer
+// to an object of (at least) that size, e.g. a buffer with that capacity.
+//
+// The cleaner function receives a pointer to an object and cleans it, same as in NewPool.
+func NewBucketedPool[T any](config *PoolConfig[T], sizes []int, allocator func(size int) T, cleaner func(T)) (*BucketedPool[T], error) {
+	if len(sizes) == 0 {
+		return nil, fmt.Errorf("at least one size class is required")
+	}
+
+	if allocator == nil {
+		return nil, fmt.Errorf("%w: allocator function is nil", ErrInvalidConfig)
+	}
+
+	sorted := slices.Clone(sizes)
+	slices.Sort(sorted)
+	for i, size := range sorted {
+		if size <= 0 {
+			return nil, fmt.Errorf("size classes must be greater than 0, got %d", size)
+		}
+
+		if i > 0 && size == sorted[i-1] {
+			return nil, fmt.Errorf("duplicate size class %d", size)
+		}
+	}
+
+	bp := &BucketedPool[T]{
+		sizes:   sorted,
+		pools:   make([]*Pool[T], 0, len(sorted)),
+		origins: make(map[any]int),
+	}
+
+	for _, size := range sorted {
+		p, err := NewPool(config, func() T { return allocator(size) }, cleaner, nil)
+		if err != nil {
+			bp.Close()
+			return nil, fmt.Errorf("size class %d: %w", size, err)
+		}
+
+		bp.pools = append(bp.pools, p.(*Pool[T]))
+	}
+
+	return bp, nil
+}
+
+// Get returns an object from the smallest size class that fits size.
+// Returns an error if size is larger than the largest size class.
+func (bp *BucketedPool[T]) Get(size int) (zero T, err error) {
+	idx, ok := slices.BinarySearch(bp.sizes, size)
+	if !ok && idx == len(bp.sizes) {
+		return zero, fmt.Errorf("size %d exceeds the largest size class %d", size, bp.sizes[len(bp.sizes)-1])
+	}
+
+	obj, err := bp.pools[idx].Get()
+	if err != nil {
+		return zero, err
+	}
+
+	bp.mu.Lock()
+	bp.origins[obj] = idx
+	bp.mu.Unlock()
+
+	return obj, nil
+}
+
+// Put returns an object to the size class it was taken from.
+// Returns an error if the object wasn't checked out of this pool.
+func (bp *BucketedPool[T]) Put(obj T) error {
+	bp.mu.Lock()
+	idx, ok := bp.origins[obj]
+	delete(bp.origins, obj)
+	bp.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("object was not checked out of this bucketed pool")
+	}
+
+	return bp.pools[idx].Put(obj)
+}
+
+// Sizes returns the size classes of the pool in ascending order.
+func (bp *BucketedPool[T]) Sizes() []int {
+	return slices.Clone(bp.sizes)
+}
+
+// Bucket returns the pool backing the given size class, mainly to inspect its stats.
+func (bp *BucketedPool[T]) Bucket(size int) (*Pool[T], bool) {
+	idx, ok := slices.BinarySearch(bp.sizes, size)
+	if !ok {
+		return nil, false
+	}
+
+	return bp.pools[idx], true
+}
+
+// Close closes the pool of every size class, see Pool.Close.
+func (bp *BucketedPool[T]) Close() error {
+	var errs []error
+	for _, p := range bp.pools {
+		if err := p.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
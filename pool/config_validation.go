@@ -8,6 +8,13 @@ import (
 // - initialCapacity must be positive
 // - hardLimit must be positive and greater than initialCapacity
 // - hardLimit must be greater than or equal to minCapacity
+// - maxObjectAge must be non-negative
+// - maxInFlight must be non-negative
+// - maxAllocRate must be non-negative
+// - asyncCleanQueue must be non-negative
+// - lockFree can't be combined with maxObjectAge or sizeOf
+// - copyOnGet can't be combined with maxObjectAge
+// - versioning can't be combined with lockFree
 // Returns an error if any validation fails.
 func (b *poolConfigBuilder[T]) validateBasicConfig() error {
 	if b.config.initialCapacity <= 0 {
@@ -26,6 +33,38 @@ func (b *poolConfigBuilder[T]) validateBasicConfig() error {
 		return fmt.Errorf("hardLimit (%d) must be >= minCapacity (%d)", b.config.hardLimit, b.config.shrink.minCapacity)
 	}
 
+	if b.config.maxObjectAge < 0 {
+		return fmt.Errorf("maxObjectAge must be >= 0, got %v", b.config.maxObjectAge)
+	}
+
+	if b.config.maxInFlight < 0 {
+		return fmt.Errorf("maxInFlight must be >= 0, got %d", b.config.maxInFlight)
+	}
+
+	if b.config.maxAllocRate < 0 {
+		return fmt.Errorf("maxAllocRate must be >= 0, got %d", b.config.maxAllocRate)
+	}
+
+	if b.config.asyncCleanQueue < 0 {
+		return fmt.Errorf("asyncCleanQueue must be >= 0, got %d", b.config.asyncCleanQueue)
+	}
+
+	if b.config.lockFree && b.config.maxObjectAge > 0 {
+		return fmt.Errorf("lockFree can't be combined with maxObjectAge")
+	}
+
+	if b.config.lockFree && b.config.sizeOf != nil {
+		return fmt.Errorf("lockFree can't be combined with sizeOf")
+	}
+
+	if b.config.copyOnGet != nil && b.config.maxObjectAge > 0 {
+		return fmt.Errorf("copyOnGet can't be combined with maxObjectAge")
+	}
+
+	if b.config.versioning && b.config.lockFree {
+		return fmt.Errorf("versioning can't be combined with lockFree")
+	}
+
 	return nil
 }
 
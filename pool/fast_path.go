@@ -2,6 +2,7 @@ package pool
 
 import (
 	"fmt"
+	"log"
 )
 
 // calculateNewCapacity determines the new capacity based on current capacity and growth configuration
@@ -103,6 +104,36 @@ func (p *Pool[T]) tryGetFromL1(locked bool) (zero T, found bool) {
 	}
 }
 
+// drainL1 takes every object currently in the L1 cache channel, it also reports whether the channel is still open.
+func (p *Pool[T]) drainL1() (objs []T, open bool) {
+	ch := *p.cacheL1
+	for {
+		select {
+		case obj, ok := <-ch:
+			if !ok {
+				return objs, false
+			}
+			objs = append(objs, obj)
+		default:
+			return objs, true
+		}
+	}
+}
+
+// restoreL1 puts objects taken out of L1 back, spilling to the ring buffer those that no longer fit.
+func (p *Pool[T]) restoreL1(objs []T) {
+	ch := *p.cacheL1
+	for _, obj := range objs {
+		select {
+		case ch <- obj:
+		default:
+			if err := p.pool.Write(obj); err != nil {
+				log.Printf("[RESTORE] failed to restore object to the ring buffer: %v", err)
+			}
+		}
+	}
+}
+
 // tryFastPathPut attempts to quickly return an object to the L1 cache channel using a non-blocking
 // select operation. If successful, it updates hit statistics and returns true.
 // If the channel is full, it returns false to indicate a miss.
@@ -184,7 +215,7 @@ func (p *Pool[T]) shouldShrinkFastPath() bool {
 func (p *Pool[T]) adjustFastPathShrinkTarget(currentCap int) int {
 	cfg := p.config.fastPath.shrink
 	newCap := currentCap * (100 - cfg.shrinkPercent) / 100
-	inUse := int(p.stats.totalGets.Load() - (p.stats.FastReturnHit.Load() + p.stats.FastReturnMiss.Load()))
+	inUse := p.InFlight()
 
 	if newCap < cfg.minCapacity {
 		return cfg.minCapacity
@@ -259,4 +290,13 @@ func (p *Pool[T]) shrinkFastPath(newCapacity, inUse int) {
 	close(ch)
 	p.cacheL1 = &newL1
 	p.updateShrinkStats(newCapacity)
+
+	result := TrimResult{Discarded: len(ch)}
+	if p.tracker != nil || p.config.sizeOf != nil {
+		for obj := range ch {
+			result.BytesFreed += p.sizeOf(obj)
+			p.discard(obj)
+		}
+	}
+	p.recordTrim(result)
 }
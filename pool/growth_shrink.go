@@ -44,7 +44,7 @@ func (p *Pool[T]) shrinkExecution() {
 		return
 	}
 
-	inUse := int(p.stats.totalGets.Load() - (p.stats.FastReturnHit.Load() + p.stats.FastReturnMiss.Load()))
+	inUse := p.InFlight()
 	newCapacity = p.adjustMainShrinkTarget(newCapacity, inUse)
 	p.performShrink(newCapacity, inUse)
 
@@ -79,7 +79,34 @@ func (p *Pool[T]) performShrink(newCapacity, inUse int) {
 		return
 	}
 
+	bytesFreed := p.discardDroppedItems()
 	p.finalizeShrink(newRingBuffer, newCapacity)
+	p.recordTrim(TrimResult{Discarded: max(destroyedCount, 0), BytesFreed: bytesFreed})
+}
+
+// discardDroppedItems drops what the pool tracks for the items left behind in the old ring buffer,
+// which are discarded along with it once the shrink is finalized. It returns their approximate size.
+func (p *Pool[T]) discardDroppedItems() (bytesFreed int64) {
+	if p.tracker == nil && p.config.sizeOf == nil {
+		return 0
+	}
+
+	part1, part2, err := p.pool.GetAllView()
+	if err != nil {
+		return 0
+	}
+
+	for _, obj := range part1 {
+		bytesFreed += p.sizeOf(obj)
+		p.discard(obj)
+	}
+
+	for _, obj := range part2 {
+		bytesFreed += p.sizeOf(obj)
+		p.discard(obj)
+	}
+
+	return bytesFreed
 }
 
 // canShrink checks if the pool can be shrunk based on the new capacity and in-use objects
@@ -251,7 +278,7 @@ func (p *Pool[T]) validateAndWriteItems(newRingBuffer *ringbuffer.RingBuffer[T],
 func (p *Pool[T]) fillRemainingCapacity(newCapacity int) error {
 	allocAmount := newCapacity * p.config.allocationStrategy.AllocPercent / 100
 	spaceAvailable := newCapacity - (p.stats.objectsCreated - p.stats.objectsDestroyed)
-	toAdd := min(allocAmount, spaceAvailable)
+	toAdd := p.limiter.take(min(allocAmount, spaceAvailable))
 	if toAdd <= 0 {
 		return nil
 	}
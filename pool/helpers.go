@@ -1,6 +1,7 @@
 package pool
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -45,8 +46,7 @@ func (p *Pool[T]) setPoolAndBuffer(obj T, fastPathRemaining int) (int, error) {
 // calculateUtilization calculates the current utilization percentage of the pool.
 // Returns 0 if there are no objects in the pool or if the L1 cache is nil.
 func (p *Pool[T]) calculateUtilization() int {
-	inUse := p.stats.totalGets.Load() - (p.stats.FastReturnHit.Load() + p.stats.FastReturnMiss.Load())
-	return (int(inUse) / p.pool.Capacity()) * 100
+	return (p.InFlight() / p.pool.Capacity()) * 100
 }
 
 func (p *Pool[T]) isUnderUtilized() bool {
@@ -165,6 +165,8 @@ func (p *Pool[T]) refill(fillTarget int) error {
 	return nil
 }
 
+// createOnDemand allocates a batch of objects while holding the pool's lock, so the batch is shrunk
+// to the tokens the max allocation rate currently allows instead of waiting for more.
 func (p *Pool[T]) createOnDemand(fillTarget int, spaceAvailable int) error {
 	allocAmount := p.config.allocationStrategy.AllocAmount
 	allocAmount = min(allocAmount, spaceAvailable, fillTarget)
@@ -173,6 +175,11 @@ func (p *Pool[T]) createOnDemand(fillTarget int, spaceAvailable int) error {
 		return nil
 	}
 
+	allocAmount = p.limiter.take(allocAmount)
+	if allocAmount == 0 {
+		return ErrAllocRateExceeded
+	}
+
 	return p.populateL1OrBuffer(allocAmount)
 }
 
@@ -250,6 +257,58 @@ func (p *Pool[T]) SlowPathGet() (obj T, err error) {
 	return obj, fmt.Errorf("%w: %w", errRingBufferFailed, err)
 }
 
+// canReuseOnAllocFailure reports whether Get may fall back to an existing object after a failed allocation,
+// which requires the option to be enabled and at least one object to have been returned to the pool.
+func (p *Pool[T]) canReuseOnAllocFailure() bool {
+	if !p.config.reuseOnAllocFailure {
+		return false
+	}
+
+	return p.returned.Load()
+}
+
+// reuseOnAllocFailure takes an idle object after allocating a new one failed, if allowed.
+func (p *Pool[T]) reuseOnAllocFailure() (zero T, found bool) {
+	if !p.canReuseOnAllocFailure() {
+		return zero, false
+	}
+
+	obj, found := p.tryGetIdle()
+	if found {
+		p.stats.degradedGets.Add(1)
+	}
+
+	return obj, found
+}
+
+// tryGetIdle takes an idle object from L1 cache or, failing that, from the ring buffer,
+// without refilling, growing or blocking. A blocking ring buffer could wait on a read, so only L1 is tried then.
+func (p *Pool[T]) tryGetIdle() (zero T, found bool) {
+	if p.free != nil {
+		return p.getFree()
+	}
+
+	if obj, found := p.tryGetFromL1(false); found {
+		return obj, true
+	}
+
+	if p.config.ringBufferConfig.Block {
+		return zero, false
+	}
+
+	p.mu.RLock()
+	pool := p.pool
+	p.mu.RUnlock()
+
+	obj, err := pool.GetOne()
+	if err != nil {
+		return zero, false
+	}
+
+	p.stats.totalGets.Add(1)
+	return obj, true
+}
+
 func (p *Pool[T]) RingBufferCapacity() int {
 	return p.pool.Capacity()
 }
@@ -259,9 +318,7 @@ func (p *Pool[T]) RingBufferLength() int {
 }
 
 func (p *Pool[T]) hasOutstandingObjects() bool {
-	totalGets := p.stats.totalGets.Load()
-	totalReturns := p.stats.FastReturnHit.Load() + p.stats.FastReturnMiss.Load()
-	return totalReturns < totalGets
+	return p.InFlight() > 0
 }
 
 // closeAsync implements the waiting logic for closeAsync. It will attempt to wait for all
@@ -272,8 +329,7 @@ func (p *Pool[T]) closeAsync() {
 	attempts := 0
 
 	for attempts < maxAttempts {
-		totalReturns := p.stats.FastReturnHit.Load() + p.stats.FastReturnMiss.Load()
-		if totalReturns >= p.stats.totalGets.Load() {
+		if !p.hasOutstandingObjects() {
 			p.performClosure()
 			return
 		}
@@ -295,6 +351,169 @@ func (p *Pool[T]) performClosure() {
 	p.cancel()
 	p.pool.Close()
 	p.cleanupCacheL1()
+	p.cleanupFree()
+	p.tracker.reset()
+	p.stats.retainedBytes.Store(0)
+}
+
+// retain adds the size of obj to the bytes retained by the pool, when a sizeOf function is configured.
+func (p *Pool[T]) retain(obj T) {
+	if p.config.sizeOf != nil {
+		p.stats.retainedBytes.Add(int64(p.config.sizeOf(obj)))
+	}
+}
+
+// sizeOf returns the approximate size of obj in bytes, or 0 when no sizeOf function is configured.
+func (p *Pool[T]) sizeOf(obj T) int64 {
+	if p.config.sizeOf == nil {
+		return 0
+	}
+
+	return int64(p.config.sizeOf(obj))
+}
+
+// recordTrim adds the idle objects discarded by a shrink or Trim to the stats and logs them, p.mu must be held.
+func (p *Pool[T]) recordTrim(result TrimResult) {
+	if result.Discarded == 0 {
+		return
+	}
+
+	p.stats.trimmedObjects += result.Discarded
+	p.stats.trimmedBytes += result.BytesFreed
+	log.Printf("[TRIM] discarded %d idle objects, freeing ~%d bytes", result.Discarded, result.BytesFreed)
+}
+
+// release subtracts the size of obj from the bytes retained by the pool, when a sizeOf function is configured.
+func (p *Pool[T]) release(obj T) {
+	if p.config.sizeOf != nil {
+		p.stats.retainedBytes.Add(-int64(p.config.sizeOf(obj)))
+	}
+}
+
+// acquireSlot takes an in-flight slot for an object about to be checked out, blocking while
+// all slots are taken. It's a no-op unless max in-flight is configured.
+func (p *Pool[T]) acquireSlot(ctx context.Context) error {
+	if p.inFlightSlots == nil {
+		return nil
+	}
+
+	select {
+	case p.inFlightSlots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.ctx.Done():
+		return errPoolClosed
+	}
+}
+
+// tryAcquireSlot is the non-blocking version of acquireSlot, it reports whether a slot was taken.
+func (p *Pool[T]) tryAcquireSlot() bool {
+	if p.inFlightSlots == nil {
+		return true
+	}
+
+	select {
+	case p.inFlightSlots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// releaseSlot gives back the in-flight slot of an object that was returned or never handed out.
+func (p *Pool[T]) releaseSlot() {
+	if p.inFlightSlots == nil {
+		return
+	}
+
+	select {
+	case <-p.inFlightSlots:
+	default:
+	}
+}
+
+// reject cleans and discards an object that failed validation (or is stale) on Put, freeing its slot
+// so the pool can allocate a replacement.
+func (p *Pool[T]) reject(obj T) {
+	p.cleaner(obj)
+	p.tracker.forget(obj)
+
+	p.mu.Lock()
+	p.stats.objectsDestroyed++
+	p.mu.Unlock()
+
+	p.stats.totalRejected.Add(1)
+}
+
+// discard drops everything the pool tracks for an idle object it's letting go of.
+func (p *Pool[T]) discard(obj T) {
+	p.tracker.forget(obj)
+	p.release(obj)
+}
+
+// isExpired reports whether obj, just taken out of the pool, sat there for longer than maxObjectAge.
+func (p *Pool[T]) isExpired(obj T) bool {
+	maxAge := p.config.maxObjectAge
+	if maxAge <= 0 {
+		return false
+	}
+
+	stamp, ok := p.tracker.takeStamp(obj)
+	return ok && p.now().Sub(stamp) > maxAge
+}
+
+// discardExpired cleans and discards an expired or stale object that isn't being replaced (TryGet, or a failed replacement).
+// The object is never handed out, so the Get it was counted as is taken back.
+func (p *Pool[T]) discardExpired(obj T) {
+	p.cleaner(obj)
+	p.tracker.forget(obj)
+
+	p.mu.Lock()
+	p.stats.objectsDestroyed++
+	p.mu.Unlock()
+
+	p.stats.totalGets.Add(^uint64(0))
+}
+
+// isStale reports whether obj was created before the last Invalidate.
+func (p *Pool[T]) isStale(obj T) bool {
+	return p.tracker.isStale(obj, p.version.Load())
+}
+
+// replaceIfRetired returns obj unchanged unless it sat in the pool for longer than maxObjectAge
+// or was created before the last Invalidate, in which case obj is cleaned and discarded,
+// and a freshly allocated object is returned instead.
+// The pool's capacity accounting is unaffected since one object replaces the other.
+// If the replacement can't be allocated, the retired object is still discarded and the error is returned,
+// unless reuse on allocation failure is enabled and obj is only expired, in which case it's returned as is.
+func (p *Pool[T]) replaceIfRetired(obj T) (zero T, err error) {
+	expired := p.isExpired(obj)
+	stale := p.isStale(obj)
+	if !expired && !stale {
+		return obj, nil
+	}
+
+	fresh, err := p.newLimitedObject()
+	if err != nil {
+		if !stale && p.canReuseOnAllocFailure() {
+			p.stats.degradedGets.Add(1)
+			return obj, nil
+		}
+
+		p.discardExpired(obj)
+		return zero, err
+	}
+
+	p.cleaner(obj)
+	p.tracker.forget(obj)
+
+	p.mu.Lock()
+	p.stats.objectsDestroyed++
+	p.stats.objectsCreated++
+	p.mu.Unlock()
+
+	return fresh, nil
 }
 
 // GetBlockedReaders returns the number of readers currently blocked waiting for objects
@@ -304,25 +523,25 @@ func (p *Pool[T]) GetBlockedReaders() int {
 
 // tryRefillAndGetL1 attempts to refill the pool, and get an object from L1 cache.
 // It will grow in case it's allowed and needed.
-func (p *Pool[T]) tryRefillAndFromGetL1() (zero T, canProceed bool) {
+// An error is only returned when new objects couldn't be allocated (see ErrNilObject).
+func (p *Pool[T]) tryRefillAndFromGetL1() (zero T, canProceed bool, err error) {
 	select {
 	case p.refillSemaphore <- struct{}{}:
 		defer func() {
 			p.refillCond.Broadcast()
 			<-p.refillSemaphore
 		}()
-		obj, canProceed := p.handleRefillScenarios()
-		return obj, canProceed
+		return p.handleRefillScenarios()
 	default:
 		p.refillCond.L.Lock()
 		p.refillCond.Wait()
 		p.refillCond.L.Unlock()
 
 		if obj, found := p.tryGetFromL1(false); found {
-			return obj, true
+			return obj, true, nil
 		}
 
-		return zero, false
+		return zero, false, nil
 	}
 }
 
@@ -334,57 +553,81 @@ func (p *Pool[T]) tryGetFromL1IfWellStocked(currentPercent int) (obj T, found bo
 	return obj, false
 }
 
-// tryCreateAndGetFromL1 attempts to create new objects and get one from L1 cache
-func (p *Pool[T]) tryCreateAndGetFromL1(fillTarget int) (obj T, found bool) {
+// tryCreateAndGetFromL1 attempts to create new objects and get one from L1 cache.
+// Allocation failures are only reported when no object was obtained.
+func (p *Pool[T]) tryCreateAndGetFromL1(fillTarget int) (obj T, found bool, err error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	spaceAvailable := p.pool.Capacity() - (p.stats.objectsCreated - p.stats.objectsDestroyed)
 	if spaceAvailable <= 0 {
-		return obj, false
+		return obj, false, nil
 	}
 
 	if err := p.createOnDemand(fillTarget, spaceAvailable); err != nil {
-		return obj, false
+		if obj, found := p.tryGetFromL1(true); found {
+			return obj, true, nil
+		}
+		return obj, false, allocationError(err)
 	}
 
-	return p.tryGetFromL1(true)
+	obj, found = p.tryGetFromL1(true)
+	return obj, found, nil
 }
 
-// tryRefillAndGetFromL1 attempts to refill from main pool and get from L1 cache
-func (p *Pool[T]) tryRefillAndGetFromL1(fillTarget int) (obj T, found bool) {
+// tryRefillAndGetFromL1 attempts to refill from main pool and get from L1 cache.
+// Allocation failures hit while growing are reported to the caller.
+func (p *Pool[T]) tryRefillAndGetFromL1(fillTarget int) (obj T, found bool, err error) {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
 	ableToRefill, err := p.tryRefill(fillTarget)
 	if !ableToRefill && err != nil {
 		if obj, shouldContinue := p.handleRefillFailure(err); !shouldContinue {
-			return obj, false
+			return obj, false, allocationError(err)
 		}
 	}
 
-	return p.tryGetFromL1(true)
+	obj, found = p.tryGetFromL1(true)
+	return obj, found, nil
 }
 
-func (p *Pool[T]) handleRefillScenarios() (zero T, canProceed bool) {
+// allocationError returns err if it was caused by a failed allocation, and nil otherwise,
+// so that only allocation failures are surfaced to the caller of Get.
+func allocationError(err error) error {
+	if isAllocationFailure(err) {
+		return err
+	}
+
+	return nil
+}
+
+// isAllocationFailure reports whether err means new objects couldn't be allocated.
+func isAllocationFailure(err error) bool {
+	return errors.Is(err, ErrNilObject) || errors.Is(err, ErrAllocRateExceeded) || errors.Is(err, ErrAllocatorPanic)
+}
+
+func (p *Pool[T]) handleRefillScenarios() (zero T, canProceed bool, err error) {
 	p.mu.RLock()
 	currentCap, currentPercent := p.calculateL1Usage()
 	fillTarget := p.calculateFillTarget(currentCap)
 	p.mu.RUnlock()
 
 	if obj, found := p.tryGetFromL1IfWellStocked(currentPercent); found {
-		return obj, true
+		return obj, true, nil
 	}
 
-	if obj, found := p.tryCreateAndGetFromL1(fillTarget); found {
-		return obj, true
+	obj, found, createErr := p.tryCreateAndGetFromL1(fillTarget)
+	if found {
+		return obj, true, nil
 	}
 
-	if obj, found := p.tryRefillAndGetFromL1(fillTarget); found {
-		return obj, true
+	obj, found, refillErr := p.tryRefillAndGetFromL1(fillTarget)
+	if found {
+		return obj, true, nil
 	}
 
-	return zero, false
+	return zero, false, errors.Join(createErr, refillErr)
 }
 
 func checkConfigForNil[T any](config *PoolConfig[T]) error {
@@ -409,7 +652,7 @@ func checkConfigForNil[T any](config *PoolConfig[T]) error {
 
 func (p *Pool[T]) handleRefillFailure(refillError error) (T, bool) {
 	var zero T
-	if errors.Is(refillError, errRingBufferFailed) || errors.Is(refillError, errNilObject) {
+	if errors.Is(refillError, errRingBufferFailed) || isAllocationFailure(refillError) {
 		return zero, false
 	}
 
@@ -443,3 +686,87 @@ func (p *Pool[T]) IsFastPathGrowth() bool {
 func (p *Pool[T]) IsGrowth() bool {
 	return p.IsRingBufferGrowth() || p.IsFastPathGrowth()
 }
+
+// copyForGet returns obj unchanged unless copy-on-get is configured, in which case it returns a copy of obj
+// and puts the pristine obj back in the pool. A nil copy is never handed out, ErrNilObject is returned instead.
+func (p *Pool[T]) copyForGet(obj T) (zero T, err error) {
+	if p.config.copyOnGet == nil {
+		return obj, nil
+	}
+
+	copied := p.config.copyOnGet(obj)
+	p.restoreOriginal(obj)
+
+	if isNil(copied) {
+		p.stats.totalGets.Add(^uint64(0))
+		return zero, ErrNilObject
+	}
+
+	return copied, nil
+}
+
+// restoreOriginal puts back an object copied by copyForGet. The Get stays counted, since it's the copy's
+// Put that completes it.
+func (p *Pool[T]) restoreOriginal(obj T) {
+	p.retain(obj)
+
+	if p.free != nil {
+		p.free.Put(obj)
+		return
+	}
+
+	p.mu.RLock()
+	p.restoreL1([]T{obj})
+	p.mu.RUnlock()
+
+	p.pool.WakeUpOneReader()
+}
+
+// dropCopy cleans a copy handed out by copy-on-get and lets it go, counting the Get it was handed out by as returned.
+func (p *Pool[T]) dropCopy(obj T) {
+	p.cleaner(obj)
+	p.stats.FastReturnHit.Add(1)
+}
+
+// enqueueClean hands a returned object to the background cleaner when async cleaning is configured,
+// it reports false when the object must be cleaned synchronously, either because it isn't or the queue is full.
+func (p *Pool[T]) enqueueClean(obj T) bool {
+	if p.cleanQueue == nil {
+		return false
+	}
+
+	select {
+	case p.cleanQueue <- obj:
+		return true
+	default:
+		return false
+	}
+}
+
+// discardQueued cleans and discards the objects waiting in the async clean queue, counting their Puts
+// as rejected since they never make it back into the pool. p.mu must be held.
+func (p *Pool[T]) discardQueued() {
+	for {
+		select {
+		case obj := <-p.cleanQueue:
+			p.cleaner(obj)
+			p.tracker.forget(obj)
+			p.stats.objectsDestroyed++
+			p.stats.totalRejected.Add(1)
+		default:
+			return
+		}
+	}
+}
+
+// drainCleanQueue cleans the objects left in the async clean queue when the pool closes.
+func (p *Pool[T]) drainCleanQueue() {
+	for {
+		select {
+		case obj := <-p.cleanQueue:
+			p.cleaner(obj)
+		default:
+			return
+		}
+	}
+}
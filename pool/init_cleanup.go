@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"time"
 
 	"github.com/AlexsanderHamir/ringbuffer"
 	config "github.com/AlexsanderHamir/ringbuffer/config"
@@ -52,25 +53,34 @@ func initializePoolStats[T any](config *PoolConfig[T]) *poolStats {
 
 // validateType validates the provided allocator, cleaner, and cloneTemplate functions.
 // This is a critical validation as the pool requires pointer types for proper object management.
-// Returns an error if the allocator returns a non-pointer type.
+// Value types (and interfaces such as any) are rejected: storing them would copy or box them on every Get and Put,
+// allocating exactly what the pool is meant to save, so they must be pooled by their pointer instead.
+// Returns an error wrapping ErrInvalidConfig if the allocator returns a non-pointer type.
 func validate[T any](allocator func() T, cleaner func(T), cloner func(T) T) error {
-	var zero T
-	if reflect.TypeOf(zero).Kind() != reflect.Ptr {
-		return fmt.Errorf("type T must be a pointer type, got %T", zero)
+	if typ := reflect.TypeFor[T](); typ.Kind() != reflect.Ptr {
+		return fmt.Errorf("%w: type T must be a pointer type, got %v (pool value types by their pointer, e.g. *%v)", ErrInvalidConfig, typ, typ)
+	}
+
+	if allocator == nil {
+		return fmt.Errorf("%w: allocator function is nil", ErrInvalidConfig)
 	}
 
 	obj := allocator()
 	if reflect.TypeOf(obj).Kind() != reflect.Ptr {
-		return fmt.Errorf("type returned by allocator must be a pointer type, got %T", obj)
+		return fmt.Errorf("%w: type returned by allocator must be a pointer type, got %T", ErrInvalidConfig, obj)
+	}
+
+	if isNil(obj) {
+		return fmt.Errorf("%w: allocator returned a nil object", ErrInvalidConfig)
 	}
 
 	if cleaner == nil {
-		return fmt.Errorf("cleaner function is nil")
+		return fmt.Errorf("%w: cleaner function is nil", ErrInvalidConfig)
 	}
 
 	if cloner != nil {
 		if reflect.TypeOf(cloner(obj)).Kind() != reflect.Ptr {
-			return fmt.Errorf("type returned by cloner must be a pointer type, got %T", cloner(obj))
+			return fmt.Errorf("%w: type returned by cloner must be a pointer type, got %T", ErrInvalidConfig, cloner(obj))
 		}
 	}
 
@@ -96,6 +106,20 @@ func initializePoolObject[T any](config *PoolConfig[T], allocator func() T, clea
 		pool:            ringBuffer,
 		template:        template,
 		refillCond:      sync.NewCond(&sync.Mutex{}),
+		tracker:         newObjectTracker(config),
+		now:             time.Now,
+	}
+
+	if config.maxInFlight > 0 {
+		poolObj.inFlightSlots = make(chan struct{}, config.maxInFlight)
+	}
+
+	if config.lockFree {
+		poolObj.free = &sync.Pool{}
+	}
+
+	if config.asyncCleanQueue > 0 {
+		poolObj.cleanQueue = make(chan T, config.asyncCleanQueue)
 	}
 
 	poolObj.shrinkCond = sync.NewCond(&poolObj.mu)
@@ -111,15 +135,19 @@ func (p *Pool[T]) populateL1OrBuffer(allocAmount int) error {
 	fastPathRemaining := fillTarget
 
 	for range allocAmount {
-		var obj T
-		if p.cloneTemplate != nil {
-			obj = p.cloneTemplate(p.template)
-		} else {
-			obj = p.allocator()
+		obj, err := p.newObject()
+		if err != nil {
+			return err
 		}
 		p.stats.objectsCreated++
+		p.tracker.stamp(obj, p.now())
+		p.retain(obj)
+
+		if p.free != nil {
+			p.free.Put(obj)
+			continue
+		}
 
-		var err error
 		fastPathRemaining, err = p.setPoolAndBuffer(obj, fastPathRemaining)
 		if err != nil {
 			return fmt.Errorf("failed to set pool and buffer: %w", err)
@@ -128,6 +156,60 @@ func (p *Pool[T]) populateL1OrBuffer(allocAmount int) error {
 	return nil
 }
 
+// newObject creates a new object, cloning the template when a cloner was provided
+// and calling the allocator otherwise. A nil object is retried once before giving up with ErrNilObject,
+// so a nil never makes it into circulation.
+// It doesn't apply the max allocation rate, callers do, see newLimitedObject and createOnDemand.
+// If versioning is enabled, the object is recorded as created under the pool's current version.
+// A panicking allocator is reported as ErrAllocatorPanic.
+func (p *Pool[T]) newObject() (zero T, err error) {
+	for range 2 {
+		obj, err := p.allocate()
+		if err != nil {
+			return zero, err
+		}
+
+		if !isNil(obj) {
+			p.tracker.setVersion(obj, p.version.Load())
+			return obj, nil
+		}
+	}
+
+	return zero, ErrNilObject
+}
+
+// newLimitedObject allocates a single object outside the pool's lock, and so is allowed to wait for its turn
+// when a max allocation rate is configured, or fails with ErrAllocRateExceeded.
+func (p *Pool[T]) newLimitedObject() (zero T, err error) {
+	if err := p.limiter.wait(); err != nil {
+		return zero, err
+	}
+
+	return p.newObject()
+}
+
+// allocate clones the template when a cloner was provided and calls the allocator otherwise,
+// converting a panic into an error wrapping ErrAllocatorPanic.
+func (p *Pool[T]) allocate() (obj T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrAllocatorPanic, r)
+		}
+	}()
+
+	if p.cloneTemplate != nil {
+		return p.cloneTemplate(p.template), nil
+	}
+
+	return p.allocator(), nil
+}
+
+// isNil reports whether obj is nil, T is always a pointer type (see validate).
+func isNil[T any](obj T) bool {
+	v := reflect.ValueOf(obj)
+	return !v.IsValid() || v.IsNil()
+}
+
 // cleanupCacheL1 performs cleanup of the L1 cache by:
 // 1. Draining all objects from the cache
 // 2. Calling the cleaner function on each object
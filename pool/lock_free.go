@@ -0,0 +1,55 @@
+package pool
+
+// getFree takes an idle object from the sync.Pool backing a lock-free pool, without allocating.
+func (p *Pool[T]) getFree() (zero T, found bool) {
+	obj, ok := p.free.Get().(T)
+	if !ok {
+		return zero, false
+	}
+
+	p.stats.totalGets.Add(1)
+	return obj, true
+}
+
+// lockFreeGet serves Get for a lock-free pool, allocating a new object when no idle one is available.
+// The pool's lock is only taken to count the allocation, never on the hot path.
+func (p *Pool[T]) lockFreeGet() (zero T, err error) {
+	if obj, found := p.getFree(); found {
+		return obj, nil
+	}
+
+	obj, err := p.newLimitedObject()
+	if err != nil {
+		return zero, err
+	}
+
+	p.mu.Lock()
+	p.stats.objectsCreated++
+	p.stats.currentCapacity = max(p.stats.currentCapacity, p.stats.objectsCreated-p.stats.objectsDestroyed)
+	p.mu.Unlock()
+
+	p.stats.totalGets.Add(1)
+	return obj, nil
+}
+
+// lockFreePut hands a cleaned object back to the sync.Pool backing a lock-free pool.
+func (p *Pool[T]) lockFreePut(obj T) {
+	p.free.Put(obj)
+	p.stats.FastReturnHit.Add(1)
+}
+
+// cleanupFree passes the idle objects of a lock-free pool to the cleaner when the pool closes, like cleanupCacheL1.
+// It's best effort, a sync.Pool may have already dropped some of them, or hold one out of reach of this goroutine.
+func (p *Pool[T]) cleanupFree() {
+	if p.free == nil {
+		return
+	}
+
+	for {
+		obj, ok := p.free.Get().(T)
+		if !ok {
+			return
+		}
+		p.cleaner(obj)
+	}
+}
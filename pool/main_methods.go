@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/AlexsanderHamir/ringbuffer"
@@ -13,13 +14,35 @@ var (
 	errGrowthBlocked    = errors.New("growth is blocked")
 	errRingBufferFailed = errors.New("ring buffer failed core operation")
 	errNoItemsToMove    = errors.New("no items to move")
-	errNilObject        = errors.New("object is nil")
 	errNilConfig        = errors.New("config is nil")
+	errPoolClosed       = errors.New("pool is closed")
+	errNotVersioned     = errors.New("versioning is not enabled")
+	errNegativeKeep     = errors.New("keep must not be negative")
+
+	// ErrNilObject is returned by Get when the allocator (or cloner) keeps returning nil
+	// and the pool has no object to hand out instead.
+	ErrNilObject = errors.New("object is nil")
+
+	// ErrAllocRateExceeded is returned by Get when the pool needs to allocate but the max allocation rate
+	// was reached, and it's configured not to block.
+	ErrAllocRateExceeded = errors.New("allocation rate exceeded")
+
+	// ErrDoubleRelease is returned by Put, when double release detection is enabled,
+	// for an object that isn't currently checked out of the pool.
+	ErrDoubleRelease = errors.New("object is not checked out of the pool")
+
+	// ErrAllocatorPanic is returned by Get when the allocator (or cloner) panics. The panic is recovered
+	// so that no lock or in-flight slot is left held, and the pool stays usable.
+	ErrAllocatorPanic = errors.New("allocator panicked")
+
+	// ErrInvalidConfig is returned by NewPool when it's given an unusable allocator or cleaner.
+	ErrInvalidConfig = errors.New("invalid pool configuration")
 )
 
 // NewPool creates a new object pool with the given configuration.
 //
-// The allocator function creates a new object and returns a pointer to it.
+// The allocator function creates a new object and returns a pointer to it. It must never return nil,
+// a nil allocation is retried once, and if it's still nil Get returns ErrNilObject instead of handing it out.
 //
 // The cleaner function receives a pointer to an object and cleans it.
 //
@@ -62,37 +85,192 @@ func NewPool[T any](config *PoolConfig[T], allocator func() T, cleaner func(T),
 		return nil, err
 	}
 
-	go poolObj.shrink()
+	poolObj.limiter = newAllocLimiter(config)
+
+	// a lock-free pool's idle objects are released by the garbage collector, there's nothing to shrink.
+	if poolObj.free == nil {
+		go poolObj.shrink()
+	}
+
+	if poolObj.cleanQueue != nil {
+		go poolObj.cleanAsync()
+	}
 
 	return poolObj, nil
 }
 
 // Get returns an object from the pool, either from L1 cache or the ring buffer, preferring L1.
-func (p *Pool[T]) Get() (zero T, err error) {
-	if obj, found := p.tryGetFromL1(false); found {
-		return obj, nil
+// How the configured options affect it is described on their PoolConfigBuilder setters.
+func (p *Pool[T]) Get() (T, error) {
+	return p.GetWithContext(context.Background())
+}
+
+// GetWithContext works like Get, but gives up waiting for an in-flight slot once ctx is done,
+// returning the context's error. The context only bounds that wait, so without max in-flight
+// configured it behaves exactly like Get.
+func (p *Pool[T]) GetWithContext(ctx context.Context) (zero T, err error) {
+	if p.config.recordLatency {
+		start := time.Now()
+		defer func() {
+			if err == nil {
+				p.stats.recordGetLatency(time.Since(start))
+			}
+		}()
+	}
+
+	if err := p.acquireSlot(ctx); err != nil {
+		return zero, err
+	}
+
+	defer func() {
+		if err != nil {
+			p.releaseSlot()
+		}
+	}()
+
+	obj, err := p.get()
+	if err != nil {
+		return zero, err
 	}
+	p.release(obj)
 
-	if obj, found := p.tryRefillAndFromGetL1(); found {
-		return obj, nil
+	obj, err = p.replaceIfRetired(obj)
+	if err != nil {
+		return zero, err
 	}
 
-	obj, err := p.SlowPathGet()
+	obj, err = p.copyForGet(obj)
 	if err != nil {
 		return zero, err
 	}
 
+	if p.config.resetOnGet != nil {
+		p.config.resetOnGet(obj)
+	}
+
+	p.tracker.checkOut(obj)
 	return obj, nil
 }
 
+// TryGet returns an object only if one is already idle in the pool, preferring L1 like Get.
+// Unlike Get it never allocates, grows the pool or waits, the second return value reports
+// whether an object was found. It also gives up if max in-flight is configured and reached.
+// With a blocking ring buffer, only L1 is checked, since reading the ring buffer could wait.
+// An idle object past the max object age, or created before the last Invalidate, is discarded rather than replaced,
+// and TryGet reports no object.
+func (p *Pool[T]) TryGet() (zero T, found bool) {
+	if !p.tryAcquireSlot() {
+		return zero, false
+	}
+
+	obj, found := p.tryGetIdle()
+	if !found {
+		p.releaseSlot()
+		return zero, false
+	}
+	p.release(obj)
+
+	if p.isExpired(obj) || p.isStale(obj) {
+		p.discardExpired(obj)
+		p.releaseSlot()
+		return zero, false
+	}
+
+	obj, err := p.copyForGet(obj)
+	if err != nil {
+		p.releaseSlot()
+		return zero, false
+	}
+
+	if p.config.resetOnGet != nil {
+		p.config.resetOnGet(obj)
+	}
+
+	p.tracker.checkOut(obj)
+	return obj, true
+}
+
+// get retrieves an object from L1 cache or the ring buffer, preferring L1, or from the sync.Pool of a lock-free pool.
+// When allocating is blocked by the max allocation rate, it waits for a token without holding the pool's lock and tries again.
+func (p *Pool[T]) get() (zero T, err error) {
+	if p.free != nil {
+		return p.lockFreeGet()
+	}
+
+	for {
+		if obj, found := p.tryGetFromL1(false); found {
+			return obj, nil
+		}
+
+		obj, found, err := p.tryRefillAndFromGetL1()
+		if err != nil {
+			if obj, found := p.reuseOnAllocFailure(); found {
+				return obj, nil
+			}
+
+			if errors.Is(err, ErrAllocRateExceeded) && p.limiter.waitForToken() == nil {
+				continue
+			}
+			return zero, err
+		}
+
+		if found {
+			return obj, nil
+		}
+
+		return p.SlowPathGet()
+	}
+}
+
 // Put returns an object to the pool. The object will be cleaned using the cleaner function
-// before being made available for reuse.
+// before being made available for reuse, unless a configured option discards or defers it, see PoolConfigBuilder.
 func (p *Pool[T]) Put(obj T) error {
+	checkedOut := p.tracker.checkIn(obj)
+	if !checkedOut && p.config.detectDoubleRelease {
+		return ErrDoubleRelease
+	}
+	p.returned.Store(true)
+
+	// an object that wasn't handed out (e.g. priming the pool) never took an in-flight slot.
 	defer func() {
+		if checkedOut {
+			p.releaseSlot()
+		}
 		p.refillCond.Signal()
 	}()
 
+	if p.config.copyOnGet != nil {
+		p.dropCopy(obj)
+		return nil
+	}
+
+	if p.config.validator != nil && !p.config.validator(obj) {
+		p.reject(obj)
+		return nil
+	}
+
+	if p.isStale(obj) {
+		p.reject(obj)
+		return nil
+	}
+
+	if p.enqueueClean(obj) {
+		return nil
+	}
+
+	return p.cleanAndPut(obj)
+}
+
+// cleanAndPut cleans a returned object and makes it available again, preferring L1.
+func (p *Pool[T]) cleanAndPut(obj T) error {
 	p.cleaner(obj)
+	p.tracker.stamp(obj, p.now())
+	p.retain(obj)
+
+	if p.free != nil {
+		p.lockFreePut(obj)
+		return nil
+	}
 
 	if p.tryFastPathPut(obj) {
 		p.pool.WakeUpOneReader()
@@ -102,6 +280,122 @@ func (p *Pool[T]) Put(obj T) error {
 	return p.slowPathPut(obj)
 }
 
+// Inspect calls fn for every idle object currently held by the pool, L1 first, without removing them,
+// e.g. to assert in tests that returned objects were properly cleaned. It's meant for debugging:
+// the pool's lock is held while fn runs, so fn must not call back into the pool, and L1 objects are
+// briefly taken out of the cache, so concurrent Gets may miss them while fn runs.
+// A lock-free pool's idle objects can't be enumerated, so fn is never called for them.
+func (p *Pool[T]) Inspect(fn func(T)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	l1, open := p.drainL1()
+	for _, obj := range l1 {
+		fn(obj)
+	}
+
+	if open {
+		p.restoreL1(l1)
+	}
+
+	length := p.pool.Length(false)
+	if length == 0 {
+		return
+	}
+
+	items, err := p.pool.PeekN(length)
+	if err != nil {
+		return
+	}
+
+	for _, obj := range items {
+		fn(obj)
+	}
+}
+
+// Invalidate bumps the pool's version, retiring every object created before the call: idle objects are cleaned
+// and discarded right away, along with the ones waiting for the async cleaner, and checked out ones are discarded
+// when they're Put back, counted as rejected puts. One that slips back in concurrently is discarded when it's taken out.
+// Replacements are allocated on demand, so after the allocator's logic changed (e.g. a new buffer size),
+// no object built the old way is handed out again. Requires versioning to be enabled, see SetVersioning.
+// New objects cloned from the template (see NewPool) are still based on the original template.
+func (p *Pool[T]) Invalidate() error {
+	if !p.tracker.versioned() {
+		return errNotVersioned
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.version.Add(1)
+
+	idle, open := p.drainL1()
+	if !open {
+		return errPoolClosed
+	}
+
+	if p.pool.Length(false) > 0 {
+		part1, part2, err := p.pool.GetAllView()
+		if err == nil {
+			idle = append(idle, part1...)
+			idle = append(idle, part2...)
+		}
+	}
+
+	for _, obj := range idle {
+		p.cleaner(obj)
+		p.discard(obj)
+	}
+	p.stats.objectsDestroyed += len(idle)
+
+	p.discardQueued()
+
+	return nil
+}
+
+// Trim discards idle objects until at most keep are left, e.g. to reclaim memory after a burst without waiting
+// for the pool to shrink. The capacity is unchanged, so replacements are allocated on demand. It reports how many
+// objects were discarded and, when a sizeOf function is configured, the approximate bytes freed,
+// which are also added to the TrimmedObjects and TrimmedBytes stats, like the objects discarded by shrinks.
+// A lock-free pool's idle objects can't be enumerated, so nothing is trimmed.
+func (p *Pool[T]) Trim(keep int) (TrimResult, error) {
+	var result TrimResult
+	if keep < 0 {
+		return result, errNegativeKeep
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	idle, open := p.drainL1()
+	if !open {
+		return result, errPoolClosed
+	}
+
+	if p.pool.Length(false) > 0 {
+		part1, part2, err := p.pool.GetAllView()
+		if err == nil {
+			idle = append(idle, part1...)
+			idle = append(idle, part2...)
+		}
+	}
+
+	keep = min(keep, len(idle))
+	p.restoreL1(idle[:keep])
+
+	for _, obj := range idle[keep:] {
+		result.BytesFreed += p.sizeOf(obj)
+		p.cleaner(obj)
+		p.discard(obj)
+	}
+
+	result.Discarded = len(idle) - keep
+	p.stats.objectsDestroyed += result.Discarded
+	p.recordTrim(result)
+
+	return result, nil
+}
+
 // Close closes the pool and releases all resources. If there are outstanding objects,
 // it will wait for them to be returned before closing.
 func (p *Pool[T]) Close() error {
@@ -164,6 +458,23 @@ func (p *Pool[T]) shrink() {
 	}
 }
 
+// cleanAsync is a background goroutine that cleans the objects queued by Put and returns them to the pool.
+// Objects still queued when the pool closes are only cleaned, like the ones left in L1.
+func (p *Pool[T]) cleanAsync() {
+	for {
+		select {
+		case <-p.ctx.Done():
+			p.drainCleanQueue()
+			return
+		case obj := <-p.cleanQueue:
+			if err := p.cleanAndPut(obj); err != nil {
+				log.Printf("[ASYNCCLEAN] failed to return cleaned object to the pool: %v", err)
+			}
+			p.refillCond.Signal()
+		}
+	}
+}
+
 // grow is called when the demand for objects exceeds the current capacity, if enabled.
 // It increases the pool's capacity according to the growth configuration.
 func (p *Pool[T]) grow() error {
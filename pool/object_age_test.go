@@ -0,0 +1,143 @@
+package pool
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSingleObjectPool creates a pool holding exactly one object, so every Get
+// returns the object that was last Put unless it gets replaced.
+func newSingleObjectPool(t *testing.T, maxAge time.Duration, allocated, cleaned *atomic.Int64) *Pool[*example] {
+	config, err := NewPoolConfigBuilder[*example]().
+		SetInitialCapacity(1).
+		SetHardLimit(1).
+		SetMinShrinkCapacity(1).
+		SetFastPathInitialSize(1).
+		SetAllocationStrategy(100, 1).
+		SetMaxObjectAge(maxAge).
+		Build()
+	require.NoError(t, err)
+
+	alloc := func() *example {
+		allocated.Add(1)
+		return &example{}
+	}
+
+	clean := func(e *example) {
+		cleaned.Add(1)
+		e.Name = ""
+	}
+
+	p, err := NewPool(config, alloc, clean, nil)
+	require.NoError(t, err)
+
+	return p.(*Pool[*example])
+}
+
+func TestMaxObjectAge(t *testing.T) {
+	t.Run("expired object is replaced", func(t *testing.T) {
+		var allocated, cleaned atomic.Int64
+		p := newSingleObjectPool(t, time.Minute, &allocated, &cleaned)
+		defer func() {
+			require.NoError(t, p.Close())
+		}()
+
+		clock := time.Now()
+		p.now = func() time.Time { return clock }
+
+		obj, err := p.Get()
+		require.NoError(t, err)
+		obj.Name = "stale"
+		require.NoError(t, p.Put(obj))
+
+		allocatedBefore := allocated.Load()
+		cleanedBefore := cleaned.Load()
+		clock = clock.Add(2 * time.Minute)
+
+		fresh, err := p.Get()
+		require.NoError(t, err)
+		assert.NotSame(t, obj, fresh)
+		assert.Equal(t, allocatedBefore+1, allocated.Load())
+		assert.Equal(t, cleanedBefore+1, cleaned.Load())
+
+		stats := p.GetPoolStatsSnapshot()
+		assert.Equal(t, 1, stats.ObjectsDestroyed)
+		assert.Equal(t, stats.CurrentCapacity, stats.ObjectsCreated-stats.ObjectsDestroyed)
+
+		require.NoError(t, p.Put(fresh))
+	})
+
+	t.Run("object within age limit is reused", func(t *testing.T) {
+		var allocated, cleaned atomic.Int64
+		p := newSingleObjectPool(t, time.Minute, &allocated, &cleaned)
+		defer func() {
+			require.NoError(t, p.Close())
+		}()
+
+		clock := time.Now()
+		p.now = func() time.Time { return clock }
+
+		obj, err := p.Get()
+		require.NoError(t, err)
+		require.NoError(t, p.Put(obj))
+
+		clock = clock.Add(30 * time.Second)
+
+		reused, err := p.Get()
+		require.NoError(t, err)
+		assert.Same(t, obj, reused)
+
+		require.NoError(t, p.Put(reused))
+	})
+
+	t.Run("TryGet discards an expired object without replacing it", func(t *testing.T) {
+		var allocated, cleaned atomic.Int64
+		p := newSingleObjectPool(t, time.Minute, &allocated, &cleaned)
+		defer func() {
+			require.NoError(t, p.Close())
+		}()
+
+		clock := time.Now()
+		p.now = func() time.Time { return clock }
+
+		obj, found := p.TryGet()
+		require.True(t, found)
+		require.NoError(t, p.Put(obj))
+
+		allocatedBefore := allocated.Load()
+		clock = clock.Add(2 * time.Minute)
+
+		_, found = p.TryGet()
+		assert.False(t, found)
+		assert.Equal(t, allocatedBefore, allocated.Load())
+		assert.Equal(t, 0, p.InFlight())
+		assert.Equal(t, 1, p.GetPoolStatsSnapshot().ObjectsDestroyed)
+	})
+
+	t.Run("zero disables the age limit", func(t *testing.T) {
+		var allocated, cleaned atomic.Int64
+		p := newSingleObjectPool(t, 0, &allocated, &cleaned)
+		defer func() {
+			require.NoError(t, p.Close())
+		}()
+
+		clock := time.Now()
+		p.now = func() time.Time { return clock }
+
+		obj, err := p.Get()
+		require.NoError(t, err)
+		require.NoError(t, p.Put(obj))
+
+		clock = clock.Add(24 * time.Hour)
+
+		reused, err := p.Get()
+		require.NoError(t, err)
+		assert.Same(t, obj, reused)
+
+		require.NoError(t, p.Put(reused))
+	})
+}
@@ -0,0 +1,161 @@
+package pool
+
+import (
+	"sync"
+	"time"
+)
+
+// objectTracker keeps per-object metadata for features that need to know more about
+// a pooled object than the object itself carries (e.g. when it was last returned).
+// Objects are stored as-is in L1 and the ring buffer, so the metadata lives here,
+// keyed by the object's pointer identity.
+//
+// A nil tracker is valid and makes every method a no-op, so pools that don't enable
+// any tracking feature pay nothing for it.
+type objectTracker struct {
+	mu sync.Mutex
+
+	// stamps holds the time each idle object entered the pool (allocation or Put),
+	// nil unless a max object age is configured.
+	stamps map[any]time.Time
+
+	// checkedOut holds the objects currently handed out by the pool,
+	// nil unless double release detection or max in-flight is enabled.
+	checkedOut map[any]struct{}
+
+	// versions holds the pool version each object was created under,
+	// nil unless versioning is enabled.
+	versions map[any]uint64
+}
+
+// newObjectTracker returns a tracker for the features enabled in config,
+// or nil when none of them need per-object metadata.
+func newObjectTracker[T any](config *PoolConfig[T]) *objectTracker {
+	if config.maxObjectAge <= 0 && !config.detectDoubleRelease && config.maxInFlight <= 0 && !config.versioning {
+		return nil
+	}
+
+	t := &objectTracker{}
+	if config.maxObjectAge > 0 {
+		t.stamps = make(map[any]time.Time)
+	}
+
+	if config.detectDoubleRelease || config.maxInFlight > 0 {
+		t.checkedOut = make(map[any]struct{})
+	}
+
+	if config.versioning {
+		t.versions = make(map[any]uint64)
+	}
+
+	return t
+}
+
+// stamp records the time obj entered the pool.
+func (t *objectTracker) stamp(obj any, now time.Time) {
+	if t == nil || t.stamps == nil {
+		return
+	}
+
+	t.mu.Lock()
+	t.stamps[obj] = now
+	t.mu.Unlock()
+}
+
+// takeStamp returns and removes the time obj entered the pool.
+func (t *objectTracker) takeStamp(obj any) (time.Time, bool) {
+	if t == nil || t.stamps == nil {
+		return time.Time{}, false
+	}
+
+	t.mu.Lock()
+	stamp, ok := t.stamps[obj]
+	delete(t.stamps, obj)
+	t.mu.Unlock()
+
+	return stamp, ok
+}
+
+// checkOut records that obj was handed out by the pool.
+func (t *objectTracker) checkOut(obj any) {
+	if t == nil || t.checkedOut == nil {
+		return
+	}
+
+	t.mu.Lock()
+	t.checkedOut[obj] = struct{}{}
+	t.mu.Unlock()
+}
+
+// checkIn records that obj is being returned to the pool, reporting false
+// if it isn't currently checked out (already returned, or never handed out by the pool).
+func (t *objectTracker) checkIn(obj any) bool {
+	if t == nil || t.checkedOut == nil {
+		return true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.checkedOut[obj]; !ok {
+		return false
+	}
+
+	delete(t.checkedOut, obj)
+	return true
+}
+
+// versioned reports whether the tracker records object versions.
+func (t *objectTracker) versioned() bool {
+	return t != nil && t.versions != nil
+}
+
+// setVersion records the pool version obj was created under.
+func (t *objectTracker) setVersion(obj any, version uint64) {
+	if t == nil || t.versions == nil {
+		return
+	}
+
+	t.mu.Lock()
+	t.versions[obj] = version
+	t.mu.Unlock()
+}
+
+// isStale reports whether obj was created under a version older than current.
+// Objects the tracker doesn't know about (e.g. copies handed out by copy-on-get) are never stale.
+func (t *objectTracker) isStale(obj any, current uint64) bool {
+	if t == nil || t.versions == nil {
+		return false
+	}
+
+	t.mu.Lock()
+	version, ok := t.versions[obj]
+	t.mu.Unlock()
+
+	return ok && version < current
+}
+
+// forget drops all metadata held for obj, used when the pool discards it.
+func (t *objectTracker) forget(obj any) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	delete(t.stamps, obj)
+	delete(t.versions, obj)
+	t.mu.Unlock()
+}
+
+// reset drops the metadata of every object, used when the pool is closed.
+func (t *objectTracker) reset() {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	clear(t.stamps)
+	clear(t.checkedOut)
+	clear(t.versions)
+	t.mu.Unlock()
+}
@@ -148,3 +148,44 @@ func Benchmark_Shrink(b *testing.B) {
 		prevCap = newLen
 	}
 }
+
+func Benchmark_LockFree(b *testing.B) {
+	for _, lockFree := range []bool{false, true} {
+		name := "locked"
+		if lockFree {
+			name = "lock-free"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			config, err := NewPoolConfigBuilder[*example]().
+				SetInitialCapacity(128).
+				SetHardLimit(10_000_000).
+				SetMinShrinkCapacity(128).
+				SetLockFree(lockFree).
+				Build()
+			if err != nil {
+				b.Fatalf("Failed to create custom config: %v", err)
+			}
+
+			poolObj := setupPool(b, config)
+			defer poolObj.Close()
+
+			b.SetParallelism(100)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					obj, err := poolObj.Get()
+					if err != nil {
+						b.Fatalf("Failed to get object from pool: %v", err)
+					}
+
+					if err := poolObj.Put(obj); err != nil {
+						b.Fatalf("Failed to put object to pool: %v", err)
+					}
+				}
+			})
+		})
+	}
+}
@@ -259,6 +259,119 @@ func (b *poolConfigBuilder[T]) SetRingBufferWriteTimeout(d time.Duration) PoolCo
 	return b
 }
 
+// SetMaxObjectAge sets the maximum time an object may sit in the pool before being reused.
+// On Get, an object older than this is cleaned, discarded and replaced by a freshly allocated one.
+// A value of 0 disables the age limit.
+func (b *poolConfigBuilder[T]) SetMaxObjectAge(d time.Duration) PoolConfigBuilder[T] {
+	b.config.maxObjectAge = d
+	return b
+}
+
+// SetResetOnGet sets a hook that runs on every object returned by Get, whether recycled
+// or freshly allocated, right before it's handed to the caller. The cleaner still runs on Put;
+// use this when an object should be reset right before use instead of (or in addition to) after.
+func (b *poolConfigBuilder[T]) SetResetOnGet(reset func(T)) PoolConfigBuilder[T] {
+	b.config.resetOnGet = reset
+	return b
+}
+
+// SetSizeOf sets a function reporting the approximate size of an object in bytes (e.g. cap(buf)).
+// When set, the pool keeps a running total of the bytes retained by its idle objects,
+// reported as RetainedBytes in the stats snapshot.
+func (b *poolConfigBuilder[T]) SetSizeOf(sizeOf func(T) int) PoolConfigBuilder[T] {
+	b.config.sizeOf = sizeOf
+	return b
+}
+
+// SetValidator sets a check that runs on every object passed to Put, before the cleaner.
+// Objects for which it returns false (e.g. a connection that errored) are cleaned and discarded
+// instead of being returned to the pool, and the pool is free to allocate a replacement.
+func (b *poolConfigBuilder[T]) SetValidator(validator func(T) bool) PoolConfigBuilder[T] {
+	b.config.validator = validator
+	return b
+}
+
+// SetMaxInFlight caps how many objects can be checked out of the pool at once.
+// Once n objects are outstanding, Get blocks until one is returned instead of allocating a new one,
+// which suits pools backing scarce resources. A value of 0 keeps Get unbounded.
+// Checked out objects are tracked by identity, so a Put of an object the pool didn't hand out doesn't free a slot.
+func (b *poolConfigBuilder[T]) SetMaxInFlight(n int) PoolConfigBuilder[T] {
+	b.config.maxInFlight = n
+	return b
+}
+
+// SetMaxAllocRate caps how many objects the pool allocates per second after it's created, as a safeguard
+// against unbounded growth when Gets far exceed Puts. Allocations over the limit either block until allowed
+// (block = true) or make Get fail with ErrAllocRateExceeded. A perSecond of 0 disables the limit.
+func (b *poolConfigBuilder[T]) SetMaxAllocRate(perSecond int, block bool) PoolConfigBuilder[T] {
+	b.config.maxAllocRate = perSecond
+	b.config.blockOnAllocRate = block
+	return b
+}
+
+// SetReuseOnAllocFailure makes Get fall back to an object the pool already has when allocating a new one fails,
+// instead of returning the error, once at least one object has been returned to the pool.
+// An object past the max object age is served as is, and idle objects are served without being validated again.
+// Each fallback is counted as a degraded get in the stats.
+func (b *poolConfigBuilder[T]) SetReuseOnAllocFailure(enable bool) PoolConfigBuilder[T] {
+	b.config.reuseOnAllocFailure = enable
+	return b
+}
+
+// SetDetectDoubleRelease makes the pool track the identity of every checked out object, so that Put
+// returns ErrDoubleRelease for an object that was already returned or never handed out by this pool,
+// instead of letting two callers end up with the same object. Meant for debugging, it adds a map operation
+// to every Get and Put, so it's disabled by default.
+func (b *poolConfigBuilder[T]) SetDetectDoubleRelease(enable bool) PoolConfigBuilder[T] {
+	b.config.detectDoubleRelease = enable
+	return b
+}
+
+// SetLockFree backs the pool with a sync.Pool (per-P free lists) instead of the L1 cache and ring buffer,
+// for sync.Pool-level throughput on hot pools. Get and Put behave the same, but idle objects may be dropped
+// by the garbage collector, the pool allocates past the hard limit instead of waiting and never grows or shrinks,
+// so stats like AvailableObjects and ObjectsDestroyed become approximate. It can't be combined with
+// a max object age or a sizeOf function, since both need to track idle objects the pool may silently lose.
+func (b *poolConfigBuilder[T]) SetLockFree(enable bool) PoolConfigBuilder[T] {
+	b.config.lockFree = enable
+	return b
+}
+
+// SetCopyOnGet sets a function returning a copy of an object, for pools of templates that are expensive to build
+// but must stay pristine. Get hands out a copy and leaves the original in the pool, Put cleans and drops the copy,
+// so mutations never leak back into the pool, and a validator never sees them.
+// It can't be combined with a max object age, since the originals never leave the pool.
+func (b *poolConfigBuilder[T]) SetCopyOnGet(copyFn func(T) T) PoolConfigBuilder[T] {
+	b.config.copyOnGet = copyFn
+	return b
+}
+
+// SetAsyncClean makes Put queue returned objects for a background goroutine that runs the cleaner
+// and then makes them available again, so an expensive cleaner doesn't stall the releasing goroutine.
+// The queue holds up to queueSize objects, Put falls back to cleaning synchronously when it's full. Zero disables it.
+// Queued objects count as in flight until they're back in the pool.
+func (b *poolConfigBuilder[T]) SetAsyncClean(queueSize int) PoolConfigBuilder[T] {
+	b.config.asyncCleanQueue = queueSize
+	return b
+}
+
+// SetVersioning makes the pool record which version of the pool every object was created under,
+// enabling Pool.Invalidate for hot reconfiguration: after the allocator's logic changes (e.g. a new buffer size),
+// Invalidate makes sure no object built the old way is reused. It adds a map operation to every allocation and Put,
+// and can't be combined with lock-free mode, whose idle objects can be dropped without the pool noticing.
+func (b *poolConfigBuilder[T]) SetVersioning(enable bool) PoolConfigBuilder[T] {
+	b.config.versioning = enable
+	return b
+}
+
+// SetRecordLatency makes the pool record how long every successful Get takes, from the call until the object
+// is returned (blocking and allocation included), into the fixed-bucket histogram reported as GetLatency
+// by GetPoolStatsSnapshot. It shows the tail latency averages hide, at the cost of a clock read per Get.
+func (b *poolConfigBuilder[T]) SetRecordLatency(enable bool) PoolConfigBuilder[T] {
+	b.config.recordLatency = enable
+	return b
+}
+
 // Build creates a new pool configuration with the configured settings.
 // It validates all configuration parameters and returns an error if any validation fails.
 // Returns a fully configured and validated PoolConfig instance.
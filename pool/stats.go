@@ -2,6 +2,7 @@ package pool
 
 import (
 	"fmt"
+	"math"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -23,9 +24,28 @@ type poolStats struct {
 	FastReturnHit  atomic.Uint64
 	FastReturnMiss atomic.Uint64
 
+	// totalRejected counts the objects discarded on Put for failing validation.
+	totalRejected atomic.Uint64
+
+	// degradedGets counts the Gets served with an existing object because allocating a new one failed.
+	degradedGets atomic.Uint64
+
+	// retainedBytes is the approximate size of the idle objects held by the pool,
+	// only tracked when a sizeOf function is configured.
+	retainedBytes atomic.Int64
+
+	// getLatency counts successful Gets per latency bucket (see getLatencyBounds),
+	// only recorded when latency recording is enabled.
+	getLatency [len(getLatencyBounds) + 1]atomic.Uint64
+
 	totalShrinkEvents  int
 	consecutiveShrinks int
 
+	// trimmedObjects and trimmedBytes count the idle objects discarded by shrinks and Trim, and their approximate size,
+	// the bytes are only tracked when a sizeOf function is configured.
+	trimmedObjects int
+	trimmedBytes   int64
+
 	lastShrinkTime time.Time
 
 	lastL1ResizeAtGrowthNum int
@@ -33,6 +53,51 @@ type poolStats struct {
 	currentL1Capacity       int
 }
 
+// getLatencyBounds are the upper bounds of the Get latency histogram buckets,
+// the last bucket holds everything slower than the last bound.
+var getLatencyBounds = [...]time.Duration{
+	time.Microsecond,
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+}
+
+// LatencyBucket is a bucket of the Get latency histogram, counting the Gets that took at most UpperBound
+// and longer than the previous bucket's bound. The last bucket has no bound, its UpperBound is the max duration.
+type LatencyBucket struct {
+	UpperBound time.Duration
+	Count      uint64
+}
+
+// recordGetLatency adds the latency of a successful Get to its histogram bucket.
+func (s *poolStats) recordGetLatency(d time.Duration) {
+	for i, bound := range getLatencyBounds {
+		if d <= bound {
+			s.getLatency[i].Add(1)
+			return
+		}
+	}
+
+	s.getLatency[len(getLatencyBounds)].Add(1)
+}
+
+// getLatencySnapshot returns the Get latency histogram.
+func (s *poolStats) getLatencySnapshot() []LatencyBucket {
+	buckets := make([]LatencyBucket, len(s.getLatency))
+	for i := range buckets {
+		buckets[i].UpperBound = time.Duration(math.MaxInt64)
+		if i < len(getLatencyBounds) {
+			buckets[i].UpperBound = getLatencyBounds[i]
+		}
+		buckets[i].Count = s.getLatency[i].Load()
+	}
+
+	return buckets
+}
+
 // PoolStatsSnapshot represents a snapshot of the pool's statistics at a given moment
 type PoolStatsSnapshot struct {
 	// Basic Pool Stats
@@ -43,15 +108,23 @@ type PoolStatsSnapshot struct {
 	TotalGrowthEvents int
 	ObjectsCreated    int
 	ObjectsDestroyed  int
+	RetainedBytes     int64
 
 	// Fast Return Stats
 	FastReturnHit  uint64
 	FastReturnMiss uint64
+	RejectedPuts   uint64
+	DegradedGets   uint64
+
+	// Latency Stats, nil unless latency recording is enabled
+	GetLatency []LatencyBucket
 
 	// Shrink Stats
 	TotalShrinkEvents  int
 	ConsecutiveShrinks int
 	LastShrinkTime     time.Time
+	TrimmedObjects     int
+	TrimmedBytes       int64
 
 	// L1 Cache Stats
 	LastL1ResizeAtGrowthNum int
@@ -75,6 +148,7 @@ func (p *Pool[T]) PrintPoolStats() {
 	fmt.Printf("Objects in use: %d\n", stats.ObjectsInUse)
 	fmt.Printf("Objects created: %d\n", stats.ObjectsCreated)
 	fmt.Printf("Objects destroyed: %d\n", stats.ObjectsDestroyed)
+	fmt.Printf("Retained bytes: %d\n", stats.RetainedBytes)
 	fmt.Printf("Available objects: %d\n", stats.AvailableObjects)
 	fmt.Printf("Current capacity: %d\n", stats.CurrentCapacity)
 	fmt.Printf("Ring buffer length: %d\n", stats.RingBufferLength)
@@ -82,16 +156,59 @@ func (p *Pool[T]) PrintPoolStats() {
 	fmt.Printf("Total growth events: %d\n", stats.TotalGrowthEvents)
 	fmt.Printf("Total shrink events: %d\n", stats.TotalShrinkEvents)
 	fmt.Printf("Consecutive shrinks: %d\n", stats.ConsecutiveShrinks)
+	fmt.Printf("Trimmed objects: %d\n", stats.TrimmedObjects)
+	fmt.Printf("Trimmed bytes: %d\n", stats.TrimmedBytes)
 	fmt.Printf("L1 cache capacity: %d\n", stats.CurrentL1Capacity)
 	fmt.Printf("L1 cache length: %d\n", stats.L1Length)
 	fmt.Printf("Fast return hit: %d\n", stats.FastReturnHit)
 	fmt.Printf("Fast return miss: %d\n", stats.FastReturnMiss)
+	fmt.Printf("Rejected puts: %d\n", stats.RejectedPuts)
+	fmt.Printf("Degraded gets: %d\n", stats.DegradedGets)
+	for _, bucket := range stats.GetLatency {
+		fmt.Printf("Get latency <= %v: %d\n", bucket.UpperBound, bucket.Count)
+	}
 	fmt.Printf("L2 spill rate: %.2f%%\n", stats.L2SpillRate*100)
 	fmt.Printf("Utilization: %.2f%%\n", stats.Utilization)
 	fmt.Printf("Last shrink time: %v\n", stats.LastShrinkTime)
 	fmt.Println("===================")
 }
 
+// InFlight returns the number of objects currently checked out of the pool (Gets minus Puts),
+// where objects rejected on Put count as returned.
+// A count that stays high for too long usually means objects are leaking instead of being returned.
+func (p *Pool[T]) InFlight() int {
+	totalGets := p.stats.totalGets.Load()
+	totalReturns := p.stats.FastReturnHit.Load() + p.stats.FastReturnMiss.Load() + p.stats.totalRejected.Load()
+	return int(totalGets) - int(totalReturns)
+}
+
+// ResetStats zeroes the Get/Put counters (TotalGets, FastReturnHit, FastReturnMiss, RejectedPuts and DegradedGets)
+// and the GetLatency histogram,
+// so that successive runs against a long-lived pool can be measured independently.
+// Objects still checked out stay counted in TotalGets, keeping InFlight (and Close) correct,
+// and the pool's objects, capacity and growth/shrink history are left untouched.
+// It's safe to call concurrently with Get and Put, counters never go negative.
+func (p *Pool[T]) ResetStats() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Only completed cycles are subtracted, returns first, so concurrent Gets and Puts are never lost
+	// and InFlight can only be overestimated while the reset is in progress.
+	hit := p.stats.FastReturnHit.Load()
+	miss := p.stats.FastReturnMiss.Load()
+	rejected := p.stats.totalRejected.Load()
+
+	p.stats.FastReturnHit.Add(^(hit - 1))
+	p.stats.FastReturnMiss.Add(^(miss - 1))
+	p.stats.totalRejected.Add(^(rejected - 1))
+	p.stats.totalGets.Add(^(hit + miss + rejected - 1))
+	p.stats.degradedGets.Store(0)
+
+	for i := range p.stats.getLatency {
+		p.stats.getLatency[i].Store(0)
+	}
+}
+
 // GetPoolStatsSnapshot returns a snapshot of the current pool statistics
 func (p *Pool[T]) GetPoolStatsSnapshot() *PoolStatsSnapshot {
 	fastReturnHit := p.stats.FastReturnHit.Load()
@@ -107,13 +224,19 @@ func (p *Pool[T]) GetPoolStatsSnapshot() *PoolStatsSnapshot {
 	ch := *chPtr
 	l1Len := len(ch)
 
-	totalPuts := p.stats.FastReturnHit.Load() + p.stats.FastReturnMiss.Load()
+	rejectedPuts := p.stats.totalRejected.Load()
+	totalPuts := p.stats.FastReturnHit.Load() + p.stats.FastReturnMiss.Load() + rejectedPuts
 	totalGets := p.stats.totalGets.Load()
 	objectsInUse := totalGets - totalPuts
 
 	objectsCreated := p.stats.objectsCreated
 	objectsDestroyed := p.stats.objectsDestroyed
 
+	var getLatency []LatencyBucket
+	if p.config.recordLatency {
+		getLatency = p.stats.getLatencySnapshot()
+	}
+
 	return &PoolStatsSnapshot{
 		// Basic Pool Stats
 		InitialCapacity:   p.stats.initialCapacity,
@@ -123,15 +246,23 @@ func (p *Pool[T]) GetPoolStatsSnapshot() *PoolStatsSnapshot {
 		TotalGrowthEvents: p.stats.totalGrowthEvents,
 		ObjectsCreated:    objectsCreated,
 		ObjectsDestroyed:  objectsDestroyed,
+		RetainedBytes:     p.stats.retainedBytes.Load(),
 
 		// Fast Return Stats
 		FastReturnHit:  fastReturnHit,
 		FastReturnMiss: fastReturnMiss,
+		RejectedPuts:   rejectedPuts,
+		DegradedGets:   p.stats.degradedGets.Load(),
+
+		// Latency Stats
+		GetLatency: getLatency,
 
 		// Shrink Stats
 		TotalShrinkEvents:  p.stats.totalShrinkEvents,
 		ConsecutiveShrinks: p.stats.consecutiveShrinks,
 		LastShrinkTime:     p.stats.lastShrinkTime,
+		TrimmedObjects:     p.stats.trimmedObjects,
+		TrimmedBytes:       p.stats.trimmedBytes,
 
 		// L1 Cache Stats
 		LastL1ResizeAtGrowthNum: p.stats.lastL1ResizeAtGrowthNum,
@@ -147,8 +278,32 @@ func (p *Pool[T]) GetPoolStatsSnapshot() *PoolStatsSnapshot {
 	}
 }
 
+// GetLatencyPercentile returns the upper bound of the GetLatency bucket holding the given percentile (0-100)
+// of the recorded Gets, e.g. 99 for p99, or 0 if no latency was recorded.
+func (s *PoolStatsSnapshot) GetLatencyPercentile(percentile float64) time.Duration {
+	var total uint64
+	for _, bucket := range s.GetLatency {
+		total += bucket.Count
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	rank := uint64(math.Ceil(percentile / 100 * float64(total)))
+	var seen uint64
+	for _, bucket := range s.GetLatency {
+		seen += bucket.Count
+		if seen >= max(rank, 1) {
+			return bucket.UpperBound
+		}
+	}
+
+	return s.GetLatency[len(s.GetLatency)-1].UpperBound
+}
+
 func (s *PoolStatsSnapshot) Validate(reqNum int) error {
-	totalReturns := s.FastReturnHit + s.FastReturnMiss
+	totalReturns := s.FastReturnHit + s.FastReturnMiss + s.RejectedPuts
 	if totalReturns != s.TotalGets {
 		return fmt.Errorf("total returns (%d) does not match total gets (%d)", totalReturns, s.TotalGets)
 	}
@@ -63,6 +63,30 @@ type Pool[T any] struct {
 	// template is a template object that is used to create new objects
 	template T
 
+	// tracker holds per-object metadata, nil unless a feature that needs it is enabled
+	tracker *objectTracker
+
+	// now returns the current time, used for object age checks
+	now func() time.Time
+
+	// inFlightSlots is a semaphore holding one token per checked out object, nil unless maxInFlight is set
+	inFlightSlots chan struct{}
+
+	// limiter caps the allocation rate, nil unless maxAllocRate is set
+	limiter *allocLimiter
+
+	// free holds the idle objects of a lock-free pool instead of L1 and the ring buffer, nil unless lockFree is set
+	free *sync.Pool
+
+	// cleanQueue holds returned objects waiting for the background cleaner, nil unless asyncCleanQueue is set
+	cleanQueue chan T
+
+	// version is bumped by Invalidate, objects created under an older version are discarded instead of reused
+	version atomic.Uint64
+
+	// returned is set by the first Put, it isn't a stat so ResetStats never clears it
+	returned atomic.Bool
+
 	// ctx and cancel manage the pool's lifecycle
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -100,6 +124,62 @@ type PoolConfig[T any] struct {
 
 	// allocationStrategy configures how the pool allocates objects.
 	allocationStrategy *AllocationStrategy
+
+	// maxObjectAge is the maximum time an object may sit in the pool before being reused.
+	// Objects older than this are discarded on Get and replaced by a freshly allocated one.
+	// Zero means no age limit.
+	maxObjectAge time.Duration
+
+	// resetOnGet is called on every object returned by Get, right before it's handed out.
+	// Unlike the cleaner, which runs on Put, it lets objects be reset right before use.
+	resetOnGet func(T)
+
+	// sizeOf reports the approximate size in bytes of an object, used to track how much memory
+	// the pool retains in idle objects. Nil disables the tracking.
+	sizeOf func(T) int
+
+	// validator is called on every object passed to Put. Objects it rejects are cleaned and discarded
+	// instead of being returned to the pool, keeping unusable objects out of circulation.
+	validator func(T) bool
+
+	// maxInFlight caps how many objects can be checked out at once, Get blocks once the cap is reached
+	// until an object is returned. Zero means no cap.
+	maxInFlight int
+
+	// maxAllocRate caps how many objects the pool allocates per second once it's created. Zero means no cap.
+	maxAllocRate int
+
+	// blockOnAllocRate makes allocations over maxAllocRate wait for their turn,
+	// instead of failing Get with ErrAllocRateExceeded.
+	blockOnAllocRate bool
+
+	// reuseOnAllocFailure makes Get fall back to an object the pool already has when allocating a new one fails,
+	// e.g. serving an expired object instead of failing, trading freshness for availability.
+	reuseOnAllocFailure bool
+
+	// detectDoubleRelease makes the pool track which objects are checked out, so that Put can reject
+	// an object that was already returned (or never handed out) instead of corrupting the pool.
+	detectDoubleRelease bool
+
+	// lockFree backs the pool with a sync.Pool (per-P free lists) instead of L1 and the ring buffer,
+	// trading exact size accounting, growth/shrink control and the hard limit for throughput.
+	lockFree bool
+
+	// copyOnGet makes Get hand out a copy of the pooled object while the pristine original stays in the pool,
+	// so callers can mutate what they get without poisoning the pool. Put drops the copy.
+	copyOnGet func(T) T
+
+	// asyncCleanQueue is the size of the queue feeding a background goroutine that cleans returned objects,
+	// so Put doesn't wait for an expensive cleaner. Put cleans synchronously when the queue is full. Zero disables it.
+	asyncCleanQueue int
+
+	// versioning makes the pool record the version each object was created under, so that Invalidate
+	// can retire every object created before it, including the ones checked out at the time.
+	versioning bool
+
+	// recordLatency makes the pool record how long every successful Get took, blocking and allocation included,
+	// into a fixed-bucket histogram reported in the stats snapshot. Off by default to keep Get cheap.
+	recordLatency bool
 }
 
 // Getter methods for PoolConfig
@@ -127,6 +207,62 @@ func (c *PoolConfig[T]) GetRingBufferConfig() *config.RingBufferConfig[T] {
 	return c.ringBufferConfig
 }
 
+func (c *PoolConfig[T]) GetMaxObjectAge() time.Duration {
+	return c.maxObjectAge
+}
+
+func (c *PoolConfig[T]) GetResetOnGet() func(T) {
+	return c.resetOnGet
+}
+
+func (c *PoolConfig[T]) GetSizeOf() func(T) int {
+	return c.sizeOf
+}
+
+func (c *PoolConfig[T]) GetValidator() func(T) bool {
+	return c.validator
+}
+
+func (c *PoolConfig[T]) GetMaxInFlight() int {
+	return c.maxInFlight
+}
+
+func (c *PoolConfig[T]) GetMaxAllocRate() int {
+	return c.maxAllocRate
+}
+
+func (c *PoolConfig[T]) GetBlockOnAllocRate() bool {
+	return c.blockOnAllocRate
+}
+
+func (c *PoolConfig[T]) GetReuseOnAllocFailure() bool {
+	return c.reuseOnAllocFailure
+}
+
+func (c *PoolConfig[T]) GetDetectDoubleRelease() bool {
+	return c.detectDoubleRelease
+}
+
+func (c *PoolConfig[T]) GetLockFree() bool {
+	return c.lockFree
+}
+
+func (c *PoolConfig[T]) GetCopyOnGet() func(T) T {
+	return c.copyOnGet
+}
+
+func (c *PoolConfig[T]) GetAsyncCleanQueue() int {
+	return c.asyncCleanQueue
+}
+
+func (c *PoolConfig[T]) GetVersioning() bool {
+	return c.versioning
+}
+
+func (c *PoolConfig[T]) GetRecordLatency() bool {
+	return c.recordLatency
+}
+
 // growthParameters controls how the pool expands to meet demand.
 // It supports both exponential and fixed growth strategies to balance
 // between rapid growth for high demand and controlled growth for stability.
@@ -349,3 +485,12 @@ type AllocationStrategy struct {
 	// If it exceeds the ring buffer capacity it will be adjusted to the ring buffer capacity.
 	AllocAmount int
 }
+
+// TrimResult reports what a call to Pool.Trim discarded.
+type TrimResult struct {
+	// The number of idle objects discarded
+	Discarded int
+
+	// The approximate size of the discarded objects in bytes, only reported when a sizeOf function is configured
+	BytesFreed int64
+}
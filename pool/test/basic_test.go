@@ -1,6 +1,11 @@
 package test
 
 import (
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -198,3 +203,866 @@ func TestDisabledChannelGrowth(t *testing.T) {
 		p.Put(obj)
 	}
 }
+
+func TestInFlight(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(4).
+		SetHardLimit(4).
+		SetMinShrinkCapacity(4).
+		SetFastPathInitialSize(4).
+		Build()
+	require.NoError(t, err)
+
+	allocator := func() *TestObject {
+		return &TestObject{Value: 42}
+	}
+
+	cleaner := func(obj *TestObject) {
+		obj.Value = 0
+	}
+
+	p, err := pool.NewPool(config, allocator, cleaner, nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	poolObj := p.(*pool.Pool[*TestObject])
+	assert.Equal(t, 0, poolObj.InFlight())
+
+	objects := make([]*TestObject, 3)
+	for i := range objects {
+		objects[i], err = p.Get()
+		require.NoError(t, err)
+		assert.Equal(t, i+1, poolObj.InFlight())
+	}
+
+	for i, obj := range objects {
+		require.NoError(t, p.Put(obj))
+		assert.Equal(t, len(objects)-i-1, poolObj.InFlight())
+	}
+}
+
+func TestMaxInFlight(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(4).
+		SetMinShrinkCapacity(4).
+		SetFastPathInitialSize(4).
+		SetMaxInFlight(2).
+		Build()
+	require.NoError(t, err)
+
+	allocator := func() *TestObject {
+		return &TestObject{Value: 42}
+	}
+
+	cleaner := func(obj *TestObject) {
+		obj.Value = 0
+	}
+
+	p, err := pool.NewPool(config, allocator, cleaner, nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	poolObj := p.(*pool.Pool[*TestObject])
+
+	first, err := p.Get()
+	require.NoError(t, err)
+	second, err := p.Get()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = poolObj.GetWithContext(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 2, poolObj.InFlight())
+
+	done := make(chan *TestObject)
+	go func() {
+		obj, err := p.Get()
+		assert.NoError(t, err)
+		done <- obj
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Get should block while max in-flight objects are checked out")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, p.Put(first))
+
+	var third *TestObject
+	select {
+	case third = <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Get should unblock once an object is returned")
+	}
+
+	require.NoError(t, p.Put(second))
+	require.NoError(t, p.Put(third))
+
+	t.Run("priming the pool doesn't free a slot", func(t *testing.T) {
+		first, err := p.Get()
+		require.NoError(t, err)
+		second, err := p.Get()
+		require.NoError(t, err)
+
+		require.NoError(t, p.Put(&TestObject{Value: 42}), "objects the pool didn't hand out are accepted")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = poolObj.GetWithContext(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded, "2 objects are still checked out")
+
+		require.NoError(t, p.Put(first))
+		third, err := p.Get()
+		require.NoError(t, err)
+
+		require.NoError(t, p.Put(second))
+		require.NoError(t, p.Put(third))
+	})
+}
+
+func TestTryGet(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(2).
+		SetHardLimit(4).
+		SetMinShrinkCapacity(1).
+		SetFastPathInitialSize(1).
+		SetAllocationStrategy(100, 1).
+		Build()
+	require.NoError(t, err)
+
+	var allocations int
+	allocator := func() *TestObject {
+		allocations++
+		return &TestObject{Value: 42}
+	}
+
+	cleaner := func(obj *TestObject) {
+		obj.Value = 0
+	}
+
+	p, err := pool.NewPool(config, allocator, cleaner, nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	poolObj := p.(*pool.Pool[*TestObject])
+	allocationsBefore := allocations
+
+	// one preallocated object sits in L1, the other in the ring buffer.
+	obj, found := poolObj.TryGet()
+	require.True(t, found)
+	require.NotNil(t, obj)
+
+	other, found := poolObj.TryGet()
+	require.True(t, found)
+	require.NotNil(t, other)
+
+	_, found = poolObj.TryGet()
+	assert.False(t, found, "pool is empty")
+	assert.Equal(t, allocationsBefore, allocations, "TryGet never allocates")
+	assert.Equal(t, 2, poolObj.InFlight())
+
+	require.NoError(t, p.Put(obj))
+
+	reused, found := poolObj.TryGet()
+	require.True(t, found)
+	assert.Same(t, obj, reused)
+	assert.Equal(t, allocationsBefore, allocations)
+
+	require.NoError(t, p.Put(reused))
+	require.NoError(t, p.Put(other))
+	assert.Equal(t, 0, poolObj.InFlight())
+
+	t.Run("blocking ring buffer is never read", func(t *testing.T) {
+		config, err := pool.NewPoolConfigBuilder[*TestObject]().
+			SetInitialCapacity(2).
+			SetHardLimit(4).
+			SetMinShrinkCapacity(1).
+			SetFastPathInitialSize(1).
+			SetAllocationStrategy(100, 1).
+			SetRingBufferBlocking(true).
+			Build()
+		require.NoError(t, err)
+
+		p, err := pool.NewPool(config, allocator, cleaner, nil)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, p.Close())
+		}()
+
+		poolObj := p.(*pool.Pool[*TestObject])
+
+		obj, err := p.Get()
+		require.NoError(t, err)
+		require.NoError(t, p.Put(obj))
+
+		stats := poolObj.GetPoolStatsSnapshot()
+		require.Positive(t, stats.RingBufferLength)
+
+		var idle []*TestObject
+		for range stats.L1Length {
+			obj, found := poolObj.TryGet()
+			require.True(t, found, "L1 is still served")
+			idle = append(idle, obj)
+		}
+
+		start := time.Now()
+		_, found := poolObj.TryGet()
+		assert.False(t, found, "a read from a blocking ring buffer could wait")
+		assert.Less(t, time.Since(start), 50*time.Millisecond)
+		assert.Equal(t, stats.RingBufferLength, poolObj.GetPoolStatsSnapshot().RingBufferLength)
+
+		for _, obj := range idle {
+			require.NoError(t, p.Put(obj))
+		}
+	})
+}
+
+func TestResetStats(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(4).
+		SetHardLimit(4).
+		SetMinShrinkCapacity(4).
+		SetFastPathInitialSize(4).
+		Build()
+	require.NoError(t, err)
+
+	allocator := func() *TestObject {
+		return &TestObject{Value: 42}
+	}
+
+	cleaner := func(obj *TestObject) {
+		obj.Value = 0
+	}
+
+	p, err := pool.NewPool(config, allocator, cleaner, nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	poolObj := p.(*pool.Pool[*TestObject])
+
+	exercise := func(n int) {
+		for range n {
+			obj, err := p.Get()
+			require.NoError(t, err)
+			require.NoError(t, p.Put(obj))
+		}
+	}
+
+	exercise(10)
+	held, err := p.Get()
+	require.NoError(t, err)
+
+	before := poolObj.GetPoolStatsSnapshot()
+	require.Equal(t, uint64(11), before.TotalGets)
+
+	poolObj.ResetStats()
+
+	stats := poolObj.GetPoolStatsSnapshot()
+	assert.Equal(t, uint64(1), stats.TotalGets, "the object still checked out stays counted")
+	assert.Zero(t, stats.FastReturnHit+stats.FastReturnMiss)
+	assert.Equal(t, 1, poolObj.InFlight())
+	assert.Equal(t, before.ObjectsCreated, stats.ObjectsCreated)
+	assert.Equal(t, before.CurrentCapacity, stats.CurrentCapacity)
+
+	exercise(3)
+	require.NoError(t, p.Put(held))
+
+	stats = poolObj.GetPoolStatsSnapshot()
+	assert.Equal(t, uint64(4), stats.TotalGets)
+	assert.Equal(t, uint64(4), stats.FastReturnHit+stats.FastReturnMiss)
+	assert.Equal(t, 0, poolObj.InFlight())
+}
+
+func TestResetStatsConcurrent(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(16).
+		SetMinShrinkCapacity(16).
+		SetFastPathInitialSize(16).
+		Build()
+	require.NoError(t, err)
+
+	allocator := func() *TestObject {
+		return &TestObject{Value: 42}
+	}
+
+	cleaner := func(obj *TestObject) {
+		obj.Value = 0
+	}
+
+	p, err := pool.NewPool(config, allocator, cleaner, nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	poolObj := p.(*pool.Pool[*TestObject])
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				obj, err := p.Get()
+				if assert.NoError(t, err) {
+					assert.NoError(t, p.Put(obj))
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	for {
+		select {
+		case <-done:
+			stats := poolObj.GetPoolStatsSnapshot()
+			assert.Equal(t, 0, poolObj.InFlight())
+			assert.Equal(t, stats.TotalGets, stats.FastReturnHit+stats.FastReturnMiss)
+			return
+		default:
+			poolObj.ResetStats()
+		}
+	}
+}
+
+func TestInspect(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(4).
+		SetHardLimit(4).
+		SetMinShrinkCapacity(4).
+		SetFastPathInitialSize(2).
+		SetAllocationStrategy(100, 4).
+		Build()
+	require.NoError(t, err)
+
+	allocator := func() *TestObject {
+		return &TestObject{Value: 42}
+	}
+
+	cleaner := func(obj *TestObject) {
+		obj.Value = 0
+	}
+
+	p, err := pool.NewPool(config, allocator, cleaner, nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	poolObj := p.(*pool.Pool[*TestObject])
+
+	objects := make([]*TestObject, 4)
+	for i := range objects {
+		objects[i], err = p.Get()
+		require.NoError(t, err)
+		objects[i].Value = i + 100
+	}
+
+	for _, obj := range objects[:3] {
+		require.NoError(t, p.Put(obj))
+	}
+
+	before := poolObj.GetPoolStatsSnapshot()
+	require.Equal(t, 2, before.L1Length)
+	require.Equal(t, 1, before.RingBufferLength)
+
+	seen := make(map[*TestObject]bool)
+	poolObj.Inspect(func(obj *TestObject) {
+		seen[obj] = true
+		assert.Equal(t, 0, obj.Value, "idle objects were cleaned")
+	})
+
+	assert.Len(t, seen, 3)
+	for _, obj := range objects[:3] {
+		assert.True(t, seen[obj])
+	}
+	assert.False(t, seen[objects[3]], "checked out objects aren't inspected")
+
+	after := poolObj.GetPoolStatsSnapshot()
+	assert.Equal(t, before.L1Length, after.L1Length)
+	assert.Equal(t, before.RingBufferLength, after.RingBufferLength)
+	assert.Equal(t, before.TotalGets, after.TotalGets)
+
+	require.NoError(t, p.Put(objects[3]))
+}
+
+func TestLockFree(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(2).
+		SetHardLimit(2).
+		SetMinShrinkCapacity(2).
+		SetLockFree(true).
+		Build()
+	require.NoError(t, err)
+
+	allocator := func() *TestObject {
+		return &TestObject{Value: 42}
+	}
+
+	cleaner := func(obj *TestObject) {
+		obj.Value = 0
+	}
+
+	p, err := pool.NewPool(config, allocator, cleaner, nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	poolObj := p.(*pool.Pool[*TestObject])
+
+	objects := make([]*TestObject, 8)
+	for i := range objects {
+		objects[i], err = p.Get()
+		require.NoError(t, err, "a lock-free pool allocates past the hard limit")
+		objects[i].Value = i + 1
+	}
+	assert.Equal(t, len(objects), poolObj.InFlight())
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+		assert.Equal(t, 0, obj.Value, "cleaner runs on Put")
+	}
+	assert.Equal(t, 0, poolObj.InFlight())
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				obj, err := p.Get()
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.NoError(t, p.Put(obj))
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := poolObj.GetPoolStatsSnapshot()
+	assert.Equal(t, uint64(0), stats.ObjectsInUse)
+	assert.Equal(t, stats.TotalGets, stats.FastReturnHit)
+
+	t.Run("idle objects are cleaned on Close", func(t *testing.T) {
+		var closing atomic.Bool
+		var closeCleans atomic.Int64
+		cleaner := func(obj *TestObject) {
+			if closing.Load() {
+				closeCleans.Add(1)
+			}
+			obj.Value = 0
+		}
+
+		p, err := pool.NewPool(config, allocator, cleaner, nil)
+		require.NoError(t, err)
+
+		objects := make([]*TestObject, 8)
+		for i := range objects {
+			objects[i], err = p.Get()
+			require.NoError(t, err)
+		}
+
+		for _, obj := range objects {
+			require.NoError(t, p.Put(obj))
+		}
+
+		closing.Store(true)
+		require.NoError(t, p.Close())
+
+		// a sync.Pool may drop objects at will (it does so on purpose under the race detector), so not all of them are left.
+		assert.Positive(t, closeCleans.Load(), "idle objects are passed to the cleaner")
+	})
+
+	t.Run("incompatible options are rejected", func(t *testing.T) {
+		_, err := pool.NewPoolConfigBuilder[*TestObject]().
+			SetLockFree(true).
+			SetMaxObjectAge(time.Minute).
+			Build()
+		assert.Error(t, err)
+
+		_, err = pool.NewPoolConfigBuilder[*TestObject]().
+			SetLockFree(true).
+			SetSizeOf(func(*TestObject) int { return 8 }).
+			Build()
+		assert.Error(t, err)
+	})
+}
+
+func TestInvalidate(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestBuffer]().
+		SetInitialCapacity(4).
+		SetHardLimit(4).
+		SetMinShrinkCapacity(4).
+		SetFastPathInitialSize(4).
+		SetAllocationStrategy(100, 4).
+		SetVersioning(true).
+		Build()
+	require.NoError(t, err)
+
+	var bufSize atomic.Int64
+	bufSize.Store(64)
+
+	allocator := func() *TestBuffer {
+		return &TestBuffer{Data: make([]byte, 0, bufSize.Load())}
+	}
+
+	cleaner := func(buf *TestBuffer) {
+		buf.Data = buf.Data[:0]
+	}
+
+	p, err := pool.NewPool(config, allocator, cleaner, nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	poolObj := p.(*pool.Pool[*TestBuffer])
+
+	stale := make([]*TestBuffer, 2)
+	for i := range stale {
+		stale[i], err = p.Get()
+		require.NoError(t, err)
+		require.Equal(t, 64, cap(stale[i].Data))
+	}
+
+	bufSize.Store(128)
+	require.NoError(t, poolObj.Invalidate())
+
+	stats := poolObj.GetPoolStatsSnapshot()
+	assert.Equal(t, 2, stats.ObjectsDestroyed, "idle objects are discarded right away")
+	assert.Equal(t, 0, stats.L1Length+stats.RingBufferLength)
+
+	for _, buf := range stale {
+		require.NoError(t, p.Put(buf))
+	}
+
+	stats = poolObj.GetPoolStatsSnapshot()
+	assert.Equal(t, uint64(2), stats.RejectedPuts, "checked out objects are discarded on Put")
+	assert.Equal(t, 4, stats.ObjectsDestroyed)
+	assert.Equal(t, 0, poolObj.InFlight())
+
+	for range 3 {
+		fresh := make([]*TestBuffer, 4)
+		for i := range fresh {
+			fresh[i], err = p.Get()
+			require.NoError(t, err)
+			assert.Equal(t, 128, cap(fresh[i].Data), "stale objects never reappear")
+			assert.False(t, slices.Contains(stale, fresh[i]))
+		}
+
+		for _, buf := range fresh {
+			require.NoError(t, p.Put(buf))
+		}
+	}
+
+	assert.Equal(t, uint64(2), poolObj.GetPoolStatsSnapshot().RejectedPuts, "objects created after Invalidate are kept")
+
+	t.Run("objects queued for the async cleaner are discarded", func(t *testing.T) {
+		bufSize.Store(64)
+
+		config, err := pool.NewPoolConfigBuilder[*TestBuffer]().
+			SetInitialCapacity(4).
+			SetHardLimit(4).
+			SetMinShrinkCapacity(4).
+			SetFastPathInitialSize(4).
+			SetAllocationStrategy(100, 4).
+			SetVersioning(true).
+			SetAsyncClean(4).
+			Build()
+		require.NoError(t, err)
+
+		slowCleaner := func(buf *TestBuffer) {
+			time.Sleep(20 * time.Millisecond)
+			cleaner(buf)
+		}
+
+		p, err := pool.NewPool(config, allocator, slowCleaner, nil)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, p.Close())
+		}()
+
+		poolObj := p.(*pool.Pool[*TestBuffer])
+
+		stale := make([]*TestBuffer, 2)
+		for i := range stale {
+			stale[i], err = p.Get()
+			require.NoError(t, err)
+		}
+
+		for _, buf := range stale {
+			require.NoError(t, p.Put(buf))
+		}
+
+		bufSize.Store(128)
+		require.NoError(t, poolObj.Invalidate())
+
+		fresh := make([]*TestBuffer, 2)
+		for i := range fresh {
+			fresh[i], err = p.Get()
+			require.NoError(t, err)
+			assert.Equal(t, 128, cap(fresh[i].Data))
+			assert.False(t, slices.Contains(stale, fresh[i]))
+		}
+
+		for _, buf := range fresh {
+			require.NoError(t, p.Put(buf))
+		}
+
+		require.Eventually(t, func() bool {
+			return poolObj.InFlight() == 0
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("objects put back during Invalidate are discarded when taken out", func(t *testing.T) {
+		bufSize.Store(64)
+
+		var racing atomic.Pointer[TestBuffer]
+		started := make(chan struct{})
+		gate := make(chan struct{})
+
+		gatedCleaner := func(buf *TestBuffer) {
+			if racing.CompareAndSwap(buf, nil) {
+				close(started)
+				<-gate
+			}
+			cleaner(buf)
+		}
+
+		p, err := pool.NewPool(config, allocator, gatedCleaner, nil)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, p.Close())
+		}()
+
+		poolObj := p.(*pool.Pool[*TestBuffer])
+
+		buf, err := p.Get()
+		require.NoError(t, err)
+		racing.Store(buf)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			assert.NoError(t, p.Put(buf))
+		}()
+
+		<-started
+		bufSize.Store(128)
+		require.NoError(t, poolObj.Invalidate())
+		close(gate)
+		<-done
+
+		_, found := poolObj.TryGet()
+		assert.False(t, found, "TryGet discards the stale object")
+
+		fresh, err := p.Get()
+		require.NoError(t, err)
+		assert.Equal(t, 128, cap(fresh.Data))
+		assert.NotSame(t, buf, fresh)
+		require.NoError(t, p.Put(fresh))
+
+		assert.Equal(t, 0, poolObj.InFlight())
+	})
+
+	t.Run("versioning must be enabled", func(t *testing.T) {
+		config, err := pool.NewPoolConfigBuilder[*TestBuffer]().Build()
+		require.NoError(t, err)
+
+		p, err := pool.NewPool(config, allocator, cleaner, nil)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, p.Close())
+		}()
+
+		assert.Error(t, p.(*pool.Pool[*TestBuffer]).Invalidate())
+	})
+}
+
+func TestTrim(t *testing.T) {
+	const objSize = 8
+
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(4).
+		SetHardLimit(16).
+		SetGrowthFactor(1).
+		SetFixedGrowthFactor(1).
+		SetMinShrinkCapacity(4).
+		SetFastPathInitialSize(4).
+		SetAllocationStrategy(100, 4).
+		SetSizeOf(func(*TestObject) int { return objSize }).
+		Build()
+	require.NoError(t, err)
+
+	allocator := func() *TestObject {
+		return &TestObject{Value: 42}
+	}
+
+	cleaner := func(obj *TestObject) {
+		obj.Value = 0
+	}
+
+	p, err := pool.NewPool(config, allocator, cleaner, nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	poolObj := p.(*pool.Pool[*TestObject])
+
+	objects := make([]*TestObject, 12)
+	for i := range objects {
+		objects[i], err = p.Get()
+		require.NoError(t, err)
+	}
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+
+	stats := poolObj.GetPoolStatsSnapshot()
+	require.Greater(t, stats.CurrentCapacity, 4, "the pool grew")
+	idle := stats.L1Length + stats.RingBufferLength
+	require.GreaterOrEqual(t, idle, len(objects))
+
+	result, err := poolObj.Trim(4)
+	require.NoError(t, err)
+	assert.Equal(t, idle-4, result.Discarded)
+	assert.Equal(t, int64((idle-4)*objSize), result.BytesFreed)
+
+	stats = poolObj.GetPoolStatsSnapshot()
+	assert.Equal(t, 4, stats.L1Length+stats.RingBufferLength)
+	assert.Equal(t, idle-4, stats.ObjectsDestroyed)
+	assert.Equal(t, int64(4*objSize), stats.RetainedBytes)
+	assert.Equal(t, idle-4, stats.TrimmedObjects)
+	assert.Equal(t, int64((idle-4)*objSize), stats.TrimmedBytes)
+
+	result, err = poolObj.Trim(10)
+	require.NoError(t, err)
+	assert.Zero(t, result.Discarded, "nothing to trim below keep")
+
+	for i := range objects {
+		objects[i], err = p.Get()
+		require.NoError(t, err, "trimmed objects are replaced on demand")
+	}
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+
+	_, err = poolObj.Trim(-1)
+	assert.Error(t, err)
+}
+
+func TestRecordLatency(t *testing.T) {
+	const slowAlloc = 20 * time.Millisecond
+
+	var slow atomic.Bool
+	allocator := func() *TestObject {
+		if slow.Load() {
+			time.Sleep(slowAlloc)
+		}
+		return &TestObject{Value: 42}
+	}
+
+	cleaner := func(obj *TestObject) {
+		obj.Value = 0
+	}
+
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(2).
+		SetHardLimit(10).
+		SetGrowthFactor(1).
+		SetFixedGrowthFactor(1).
+		SetMinShrinkCapacity(2).
+		SetFastPathInitialSize(2).
+		SetAllocationStrategy(100, 1).
+		SetRecordLatency(true).
+		Build()
+	require.NoError(t, err)
+
+	p, err := pool.NewPool(config, allocator, cleaner, nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	poolObj := p.(*pool.Pool[*TestObject])
+
+	objects := make([]*TestObject, 0, 4)
+	for range 2 {
+		obj, err := p.Get()
+		require.NoError(t, err)
+		objects = append(objects, obj)
+	}
+
+	slow.Store(true)
+	for range 2 {
+		obj, err := p.Get()
+		require.NoError(t, err)
+		objects = append(objects, obj)
+	}
+
+	stats := poolObj.GetPoolStatsSnapshot()
+	require.NotEmpty(t, stats.GetLatency)
+	assert.Equal(t, time.Duration(math.MaxInt64), stats.GetLatency[len(stats.GetLatency)-1].UpperBound)
+
+	var total, slowGets uint64
+	for i, bucket := range stats.GetLatency {
+		if i > 0 {
+			assert.Greater(t, bucket.UpperBound, stats.GetLatency[i-1].UpperBound, "buckets are sorted by bound")
+		}
+
+		total += bucket.Count
+		if bucket.UpperBound > slowAlloc {
+			slowGets += bucket.Count
+		}
+	}
+
+	assert.Equal(t, uint64(len(objects)), total, "every successful Get is recorded")
+	assert.Positive(t, slowGets, "a Get that allocated lands in a slow bucket")
+	assert.Greater(t, stats.GetLatencyPercentile(100), slowAlloc)
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+
+	poolObj.ResetStats()
+	assert.Zero(t, poolObj.GetPoolStatsSnapshot().GetLatencyPercentile(99), "ResetStats clears the histogram")
+
+	t.Run("disabled by default", func(t *testing.T) {
+		slow.Store(false)
+
+		config, err := pool.NewPoolConfigBuilder[*TestObject]().Build()
+		require.NoError(t, err)
+
+		p, err := pool.NewPool(config, allocator, cleaner, nil)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, p.Close())
+		}()
+
+		obj, err := p.Get()
+		require.NoError(t, err)
+		require.NoError(t, p.Put(obj))
+
+		assert.Nil(t, p.(*pool.Pool[*TestObject]).GetPoolStatsSnapshot().GetLatency)
+	})
+}
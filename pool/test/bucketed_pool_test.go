@@ -0,0 +1,93 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketedPool(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestBuffer]().
+		SetInitialCapacity(1).
+		SetHardLimit(1).
+		SetMinShrinkCapacity(1).
+		SetFastPathInitialSize(1).
+		SetAllocationStrategy(100, 1).
+		Build()
+	require.NoError(t, err)
+
+	allocator := func(size int) *TestBuffer {
+		return &TestBuffer{Data: make([]byte, 0, size)}
+	}
+
+	cleaner := func(buf *TestBuffer) {
+		buf.Data = buf.Data[:0]
+	}
+
+	bp, err := pool.NewBucketedPool(config, []int{1024, 64, 256}, allocator, cleaner)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, bp.Close())
+	}()
+
+	assert.Equal(t, []int{64, 256, 1024}, bp.Sizes())
+
+	t.Run("smallest fitting size class is selected", func(t *testing.T) {
+		for _, tc := range []struct {
+			size     int
+			expected int
+		}{
+			{size: 1, expected: 64},
+			{size: 64, expected: 64},
+			{size: 65, expected: 256},
+			{size: 256, expected: 256},
+			{size: 1000, expected: 1024},
+		} {
+			buf, err := bp.Get(tc.size)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, cap(buf.Data), "size %d", tc.size)
+			require.NoError(t, bp.Put(buf))
+		}
+	})
+
+	t.Run("objects are reused within their size class", func(t *testing.T) {
+		buf, err := bp.Get(200)
+		require.NoError(t, err)
+		require.NoError(t, bp.Put(buf))
+
+		reused, err := bp.Get(100)
+		require.NoError(t, err)
+		assert.Same(t, buf, reused)
+		require.NoError(t, bp.Put(reused))
+
+		other, err := bp.Get(10)
+		require.NoError(t, err)
+		assert.NotSame(t, buf, other)
+		require.NoError(t, bp.Put(other))
+	})
+
+	t.Run("grown objects go back to their size class", func(t *testing.T) {
+		buf, err := bp.Get(64)
+		require.NoError(t, err)
+		buf.Data = append(buf.Data, make([]byte, 500)...)
+		require.NoError(t, bp.Put(buf))
+
+		small, ok := bp.Bucket(64)
+		require.True(t, ok)
+		assert.Equal(t, 0, small.InFlight())
+
+		large, ok := bp.Bucket(1024)
+		require.True(t, ok)
+		assert.Equal(t, 0, large.InFlight())
+	})
+
+	t.Run("oversized requests and foreign objects are rejected", func(t *testing.T) {
+		_, err := bp.Get(2048)
+		assert.Error(t, err)
+
+		assert.Error(t, bp.Put(allocator(64)))
+	})
+}
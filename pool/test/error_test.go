@@ -1,6 +1,7 @@
 package test
 
 import (
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -323,3 +324,485 @@ func TestResourceCleanup(t *testing.T) {
 	assert.Equal(t, int64(objNum+validation), created.Load())
 	assert.Equal(t, int64(objNum+movedToL1), cleaned.Load())
 }
+
+func TestNilAllocation(t *testing.T) {
+	cleaner := func(obj *TestObject) {
+		obj.Value = 0
+	}
+
+	t.Run("allocator always returning nil is rejected", func(t *testing.T) {
+		allocator := func() *TestObject {
+			return nil
+		}
+
+		p, err := pool.NewPool(nil, allocator, cleaner, nil)
+		require.Error(t, err)
+		assert.Nil(t, p)
+	})
+
+	newPool := func(t *testing.T, allocator func() *TestObject) pool.PoolObj[*TestObject] {
+		config, err := pool.NewPoolConfigBuilder[*TestObject]().
+			SetInitialCapacity(2).
+			SetHardLimit(10).
+			SetGrowthFactor(1).
+			SetFixedGrowthFactor(1).
+			SetMinShrinkCapacity(2).
+			SetFastPathInitialSize(2).
+			SetAllocationStrategy(100, 2).
+			Build()
+		require.NoError(t, err)
+
+		p, err := pool.NewPool(config, allocator, cleaner, nil)
+		require.NoError(t, err)
+		return p
+	}
+
+	t.Run("nil allocation is reported instead of handed out", func(t *testing.T) {
+		// validation, template and the two preallocated objects succeed.
+		var calls atomic.Int64
+		allocator := func() *TestObject {
+			if calls.Add(1) > 4 {
+				return nil
+			}
+			return &TestObject{Value: 42}
+		}
+
+		p := newPool(t, allocator)
+		defer func() {
+			require.NoError(t, p.Close())
+		}()
+
+		objects := make([]*TestObject, 2)
+		for i := range objects {
+			obj, err := p.Get()
+			require.NoError(t, err)
+			require.NotNil(t, obj)
+			objects[i] = obj
+		}
+
+		obj, err := p.Get()
+		assert.ErrorIs(t, err, pool.ErrNilObject)
+		assert.Nil(t, obj)
+
+		for _, obj := range objects {
+			require.NoError(t, p.Put(obj))
+		}
+	})
+
+	t.Run("nil allocation is retried once", func(t *testing.T) {
+		var calls atomic.Int64
+		allocator := func() *TestObject {
+			if calls.Add(1)%2 == 0 {
+				return nil
+			}
+			return &TestObject{Value: 42}
+		}
+
+		p := newPool(t, allocator)
+		defer func() {
+			require.NoError(t, p.Close())
+		}()
+
+		objects := make([]*TestObject, 6)
+		for i := range objects {
+			obj, err := p.Get()
+			require.NoError(t, err)
+			require.NotNil(t, obj)
+			objects[i] = obj
+		}
+
+		for _, obj := range objects {
+			require.NoError(t, p.Put(obj))
+		}
+	})
+}
+
+func TestMaxAllocRate(t *testing.T) {
+	allocator := func() *TestObject {
+		return &TestObject{Value: 42}
+	}
+
+	cleaner := func(obj *TestObject) {
+		obj.Value = 0
+	}
+
+	newPool := func(t *testing.T, perSecond int, block bool) *pool.Pool[*TestObject] {
+		config, err := pool.NewPoolConfigBuilder[*TestObject]().
+			SetInitialCapacity(2).
+			SetHardLimit(100).
+			SetGrowthFactor(1).
+			SetFixedGrowthFactor(1).
+			SetMinShrinkCapacity(2).
+			SetFastPathInitialSize(2).
+			SetAllocationStrategy(100, 2).
+			SetMaxAllocRate(perSecond, block).
+			Build()
+		require.NoError(t, err)
+
+		p, err := pool.NewPool(config, allocator, cleaner, nil)
+		require.NoError(t, err)
+		return p.(*pool.Pool[*TestObject])
+	}
+
+	t.Run("non-blocking", func(t *testing.T) {
+		const rate = 5
+		p := newPool(t, rate, false)
+		defer func() {
+			require.NoError(t, p.Close())
+		}()
+
+		var (
+			objects []*TestObject
+			err     error
+		)
+		for range 20 {
+			var obj *TestObject
+			obj, err = p.Get()
+			if err != nil {
+				break
+			}
+			objects = append(objects, obj)
+		}
+
+		assert.ErrorIs(t, err, pool.ErrAllocRateExceeded)
+
+		stats := p.GetPoolStatsSnapshot()
+		assert.LessOrEqual(t, stats.ObjectsCreated, 2+rate+1, "preallocated objects plus one second worth of allocations")
+
+		for _, obj := range objects {
+			require.NoError(t, p.Put(obj))
+		}
+	})
+
+	t.Run("blocking", func(t *testing.T) {
+		const rate = 20
+		p := newPool(t, rate, true)
+		defer func() {
+			require.NoError(t, p.Close())
+		}()
+
+		start := time.Now()
+		objects := make([]*TestObject, 30)
+		for i := range objects {
+			obj, err := p.Get()
+			require.NoError(t, err)
+			objects[i] = obj
+		}
+
+		// 2 preallocated objects and a full bucket of 20, the rest wait for tokens at 20/s.
+		assert.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+
+		for _, obj := range objects {
+			require.NoError(t, p.Put(obj))
+		}
+	})
+
+	t.Run("blocked Get doesn't hold the pool lock", func(t *testing.T) {
+		const rate = 2
+
+		config, err := pool.NewPoolConfigBuilder[*TestObject]().
+			SetMaxAllocRate(rate, true).
+			Build()
+		require.NoError(t, err)
+
+		obj, err := pool.NewPool(config, allocator, cleaner, nil)
+		require.NoError(t, err)
+		p := obj.(*pool.Pool[*TestObject])
+		defer func() {
+			require.NoError(t, p.Close())
+		}()
+
+		objects := make([]*TestObject, p.GetPoolStatsSnapshot().ObjectsCreated)
+		for i := range objects {
+			objects[i], err = p.Get()
+			require.NoError(t, err)
+		}
+
+		// a full bucket of tokens is served right away, the last Get waits for a token.
+		allocated := make(chan []*TestObject)
+		go func() {
+			var objs []*TestObject
+			for range rate + 1 {
+				obj, err := p.Get()
+				assert.NoError(t, err)
+				objs = append(objs, obj)
+			}
+			allocated <- objs
+		}()
+
+		time.Sleep(50 * time.Millisecond)
+
+		start := time.Now()
+		require.NoError(t, p.Put(objects[0]))
+		assert.Less(t, time.Since(start), 100*time.Millisecond, "Put isn't stuck behind the rate limited Get")
+
+		objects = append(objects[1:], <-allocated...)
+		for _, obj := range objects {
+			require.NoError(t, p.Put(obj))
+		}
+	})
+}
+
+func TestReuseOnAllocFailure(t *testing.T) {
+	cleaner := func(obj *TestObject) {
+		obj.Value = 0
+	}
+
+	newPool := func(t *testing.T, reuse bool, failing *atomic.Bool) *pool.Pool[*TestObject] {
+		config, err := pool.NewPoolConfigBuilder[*TestObject]().
+			SetInitialCapacity(1).
+			SetHardLimit(1).
+			SetMinShrinkCapacity(1).
+			SetFastPathInitialSize(1).
+			SetAllocationStrategy(100, 1).
+			SetMaxObjectAge(10 * time.Millisecond).
+			SetReuseOnAllocFailure(reuse).
+			Build()
+		require.NoError(t, err)
+
+		allocator := func() *TestObject {
+			if failing.Load() {
+				return nil
+			}
+			return &TestObject{Value: 42}
+		}
+
+		p, err := pool.NewPool(config, allocator, cleaner, nil)
+		require.NoError(t, err)
+		return p.(*pool.Pool[*TestObject])
+	}
+
+	t.Run("stale object is served when allocation fails", func(t *testing.T) {
+		var failing atomic.Bool
+		p := newPool(t, true, &failing)
+		defer func() {
+			require.NoError(t, p.Close())
+		}()
+
+		obj, err := p.Get()
+		require.NoError(t, err)
+		require.NoError(t, p.Put(obj))
+
+		failing.Store(true)
+		time.Sleep(20 * time.Millisecond)
+
+		stale, err := p.Get()
+		require.NoError(t, err)
+		assert.Same(t, obj, stale)
+
+		stats := p.GetPoolStatsSnapshot()
+		assert.Equal(t, uint64(1), stats.DegradedGets)
+		assert.Equal(t, 0, stats.ObjectsDestroyed)
+
+		require.NoError(t, p.Put(stale))
+	})
+
+	t.Run("stale object is served after the stats were reset", func(t *testing.T) {
+		var failing atomic.Bool
+		p := newPool(t, true, &failing)
+		defer func() {
+			require.NoError(t, p.Close())
+		}()
+
+		obj, err := p.Get()
+		require.NoError(t, err)
+		require.NoError(t, p.Put(obj))
+		p.ResetStats()
+
+		failing.Store(true)
+		time.Sleep(20 * time.Millisecond)
+
+		stale, err := p.Get()
+		require.NoError(t, err)
+		assert.Same(t, obj, stale)
+
+		require.NoError(t, p.Put(stale))
+	})
+
+	t.Run("allocation failure is reported when disabled", func(t *testing.T) {
+		var failing atomic.Bool
+		p := newPool(t, false, &failing)
+		defer func() {
+			require.NoError(t, p.Close())
+		}()
+
+		obj, err := p.Get()
+		require.NoError(t, err)
+		require.NoError(t, p.Put(obj))
+
+		failing.Store(true)
+		time.Sleep(20 * time.Millisecond)
+
+		_, err = p.Get()
+		assert.ErrorIs(t, err, pool.ErrNilObject)
+		assert.Zero(t, p.GetPoolStatsSnapshot().DegradedGets)
+	})
+}
+
+func TestDetectDoubleRelease(t *testing.T) {
+	allocator := func() *TestObject {
+		return &TestObject{Value: 42}
+	}
+
+	cleaner := func(obj *TestObject) {
+		obj.Value = 0
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		config, err := pool.NewPoolConfigBuilder[*TestObject]().Build()
+		require.NoError(t, err)
+		assert.False(t, config.GetDetectDoubleRelease())
+	})
+
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(2).
+		SetHardLimit(2).
+		SetMinShrinkCapacity(2).
+		SetFastPathInitialSize(2).
+		SetAllocationStrategy(100, 2).
+		SetDetectDoubleRelease(true).
+		Build()
+	require.NoError(t, err)
+
+	p, err := pool.NewPool(config, allocator, cleaner, nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	poolObj := p.(*pool.Pool[*TestObject])
+
+	obj, err := p.Get()
+	require.NoError(t, err)
+	require.NoError(t, p.Put(obj))
+
+	t.Run("double release is caught", func(t *testing.T) {
+		assert.ErrorIs(t, p.Put(obj), pool.ErrDoubleRelease)
+		assert.Equal(t, 0, poolObj.InFlight())
+	})
+
+	t.Run("foreign object is caught", func(t *testing.T) {
+		assert.ErrorIs(t, p.Put(allocator()), pool.ErrDoubleRelease)
+	})
+
+	t.Run("pool isn't corrupted", func(t *testing.T) {
+		first, err := p.Get()
+		require.NoError(t, err)
+		second, err := p.Get()
+		require.NoError(t, err)
+		assert.NotSame(t, first, second)
+
+		require.NoError(t, p.Put(first))
+		require.NoError(t, p.Put(second))
+	})
+}
+
+func TestAllocatorPanic(t *testing.T) {
+	var panicking atomic.Bool
+	allocator := func() *TestObject {
+		if panicking.Load() {
+			panic("allocator failure")
+		}
+		return &TestObject{Value: 42}
+	}
+
+	cleaner := func(obj *TestObject) {
+		obj.Value = 0
+	}
+
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(2).
+		SetHardLimit(10).
+		SetGrowthFactor(1).
+		SetFixedGrowthFactor(1).
+		SetMinShrinkCapacity(2).
+		SetFastPathInitialSize(2).
+		SetAllocationStrategy(100, 2).
+		Build()
+	require.NoError(t, err)
+
+	p, err := pool.NewPool(config, allocator, cleaner, nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	poolObj := p.(*pool.Pool[*TestObject])
+
+	objects := make([]*TestObject, 2)
+	for i := range objects {
+		objects[i], err = p.Get()
+		require.NoError(t, err)
+	}
+
+	panicking.Store(true)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			obj, err := p.Get()
+			assert.ErrorIs(t, err, pool.ErrAllocatorPanic)
+			assert.Nil(t, obj)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, len(objects), poolObj.InFlight(), "failed Gets aren't counted as in flight")
+
+	panicking.Store(false)
+
+	obj, err := p.Get()
+	require.NoError(t, err, "the pool stays usable after the allocator panicked")
+	objects = append(objects, obj)
+
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+}
+
+func TestInvalidFunctions(t *testing.T) {
+	allocator := func() *TestObject {
+		return &TestObject{Value: 42}
+	}
+
+	cleaner := func(obj *TestObject) {
+		obj.Value = 0
+	}
+
+	t.Run("nil allocator", func(t *testing.T) {
+		p, err := pool.NewPool(nil, nil, cleaner, nil)
+		assert.ErrorIs(t, err, pool.ErrInvalidConfig)
+		assert.Nil(t, p)
+	})
+
+	t.Run("nil cleaner", func(t *testing.T) {
+		p, err := pool.NewPool(nil, allocator, nil, nil)
+		assert.ErrorIs(t, err, pool.ErrInvalidConfig)
+		assert.Nil(t, p)
+	})
+
+	t.Run("nil bucketed allocator", func(t *testing.T) {
+		bp, err := pool.NewBucketedPool[*TestObject](nil, []int{64}, nil, cleaner)
+		assert.ErrorIs(t, err, pool.ErrInvalidConfig)
+		assert.Nil(t, bp)
+	})
+
+	t.Run("value type", func(t *testing.T) {
+		p, err := pool.NewPool(nil, func() TestObject {
+			return TestObject{Value: 42}
+		}, func(obj TestObject) {}, nil)
+		assert.ErrorIs(t, err, pool.ErrInvalidConfig)
+		assert.ErrorContains(t, err, "*test.TestObject")
+		assert.Nil(t, p)
+	})
+
+	t.Run("interface type", func(t *testing.T) {
+		p, err := pool.NewPool(nil, func() any {
+			return &TestObject{Value: 42}
+		}, func(obj any) {}, nil)
+		assert.ErrorIs(t, err, pool.ErrInvalidConfig)
+		assert.Nil(t, p)
+	})
+}
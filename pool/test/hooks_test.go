@@ -0,0 +1,424 @@
+package test
+
+import (
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/PoolX/v2/pool"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResetOnGet(t *testing.T) {
+	var resets, cleans atomic.Int64
+
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(1).
+		SetHardLimit(1).
+		SetMinShrinkCapacity(1).
+		SetFastPathInitialSize(1).
+		SetAllocationStrategy(100, 1).
+		SetResetOnGet(func(obj *TestObject) {
+			resets.Add(1)
+			obj.Value = -1
+		}).
+		Build()
+	require.NoError(t, err)
+
+	allocator := func() *TestObject {
+		return &TestObject{Value: 42}
+	}
+
+	cleaner := func(obj *TestObject) {
+		cleans.Add(1)
+		obj.Value = 0
+	}
+
+	p, err := pool.NewPool(config, allocator, cleaner, nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	obj, err := p.Get()
+	require.NoError(t, err)
+	assert.Equal(t, -1, obj.Value)
+	assert.Equal(t, int64(1), resets.Load())
+	assert.Equal(t, int64(0), cleans.Load())
+
+	obj.Value = 7
+	require.NoError(t, p.Put(obj))
+	assert.Equal(t, 0, obj.Value, "cleaner runs on Put")
+	assert.Equal(t, int64(1), resets.Load(), "reset hook doesn't run on Put")
+	assert.Equal(t, int64(1), cleans.Load())
+
+	recycled, err := p.Get()
+	require.NoError(t, err)
+	assert.Same(t, obj, recycled)
+	assert.Equal(t, -1, recycled.Value)
+	assert.Equal(t, int64(2), resets.Load())
+	assert.Equal(t, int64(1), cleans.Load(), "cleaner doesn't run on Get")
+
+	require.NoError(t, p.Put(recycled))
+}
+
+func TestSizeOf(t *testing.T) {
+	const bufSize = 64
+
+	allocator := func() *TestBuffer {
+		return &TestBuffer{Data: make([]byte, 0, bufSize)}
+	}
+
+	cleaner := func(buf *TestBuffer) {
+		buf.Data = buf.Data[:0]
+	}
+
+	sizeOf := func(buf *TestBuffer) int {
+		return cap(buf.Data)
+	}
+
+	t.Run("growth", func(t *testing.T) {
+		config, err := pool.NewPoolConfigBuilder[*TestBuffer]().
+			SetInitialCapacity(2).
+			SetGrowthFactor(1).
+			SetFixedGrowthFactor(1).
+			SetMinShrinkCapacity(2).
+			SetFastPathInitialSize(2).
+			SetAllocationStrategy(100, 2).
+			SetSizeOf(sizeOf).
+			Build()
+		require.NoError(t, err)
+
+		p, err := pool.NewPool(config, allocator, cleaner, nil)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, p.Close())
+		}()
+
+		poolObj := p.(*pool.Pool[*TestBuffer])
+		assert.Equal(t, int64(2*bufSize), poolObj.GetPoolStatsSnapshot().RetainedBytes)
+
+		buffers := make([]*TestBuffer, 6)
+		for i := range buffers {
+			buffers[i], err = p.Get()
+			require.NoError(t, err)
+		}
+
+		stats := poolObj.GetPoolStatsSnapshot()
+		idle := stats.ObjectsCreated - len(buffers)
+		assert.Equal(t, int64(idle*bufSize), stats.RetainedBytes, "checked out objects aren't retained")
+
+		buffers[0].Data = make([]byte, 0, 1024)
+		for _, buf := range buffers {
+			require.NoError(t, p.Put(buf))
+		}
+
+		stats = poolObj.GetPoolStatsSnapshot()
+		assert.Equal(t, int64((stats.ObjectsCreated-1)*bufSize+1024), stats.RetainedBytes)
+	})
+
+	t.Run("shrink", func(t *testing.T) {
+		config, err := pool.NewPoolConfigBuilder[*TestBuffer]().
+			SetInitialCapacity(32).
+			EnforceCustomConfig().
+			SetShrinkCheckInterval(10*time.Millisecond).
+			SetShrinkCooldown(10*time.Millisecond).
+			SetMinUtilizationBeforeShrink(90).
+			SetStableUnderutilizationRounds(1).
+			SetShrinkPercent(50).
+			SetMinShrinkCapacity(1).
+			SetMaxConsecutiveShrinks(5).
+			SetFastPathBasicConfigs(1, 1, 1, 100, 20).
+			SetAllocationStrategy(100, 1).
+			SetSizeOf(sizeOf).
+			Build()
+		require.NoError(t, err)
+
+		p, err := pool.NewPool(config, allocator, cleaner, nil)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, p.Close())
+		}()
+
+		poolObj := p.(*pool.Pool[*TestBuffer])
+		assert.Equal(t, int64(32*bufSize), poolObj.GetPoolStatsSnapshot().RetainedBytes)
+
+		buf, err := p.Get()
+		require.NoError(t, err)
+
+		time.Sleep(300 * time.Millisecond)
+		require.True(t, poolObj.IsShrunk())
+
+		stats := poolObj.GetPoolStatsSnapshot()
+		require.Positive(t, stats.ObjectsDestroyed)
+		idle := stats.ObjectsCreated - stats.ObjectsDestroyed - int(stats.ObjectsInUse)
+		assert.Equal(t, int64(idle*bufSize), stats.RetainedBytes, "discarded objects are no longer retained")
+		assert.GreaterOrEqual(t, stats.TrimmedObjects, stats.ObjectsDestroyed)
+		assert.Equal(t, int64(stats.TrimmedObjects*bufSize), stats.TrimmedBytes, "shrinks report what they discarded")
+
+		require.NoError(t, p.Put(buf))
+	})
+}
+
+func TestValidator(t *testing.T) {
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(4).
+		SetHardLimit(4).
+		SetMinShrinkCapacity(4).
+		SetFastPathInitialSize(4).
+		SetAllocationStrategy(100, 4).
+		SetValidator(func(obj *TestObject) bool {
+			return obj.Value >= 0
+		}).
+		Build()
+	require.NoError(t, err)
+
+	var cleans atomic.Int64
+	allocator := func() *TestObject {
+		return &TestObject{Value: 42}
+	}
+
+	cleaner := func(obj *TestObject) {
+		cleans.Add(1)
+	}
+
+	p, err := pool.NewPool(config, allocator, cleaner, nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	poolObj := p.(*pool.Pool[*TestObject])
+
+	objects := make([]*TestObject, 4)
+	for i := range objects {
+		objects[i], err = p.Get()
+		require.NoError(t, err)
+	}
+
+	poisoned := objects[0]
+	poisoned.Value = -1
+	for _, obj := range objects {
+		require.NoError(t, p.Put(obj))
+	}
+
+	assert.Equal(t, int64(len(objects)), cleans.Load(), "rejected objects are cleaned too")
+	assert.Equal(t, 0, poolObj.InFlight())
+
+	stats := poolObj.GetPoolStatsSnapshot()
+	assert.Equal(t, uint64(1), stats.RejectedPuts)
+	assert.Equal(t, 1, stats.ObjectsDestroyed)
+
+	for range 3 {
+		for i := range objects {
+			objects[i], err = p.Get()
+			require.NoError(t, err)
+			assert.NotSame(t, poisoned, objects[i])
+		}
+
+		for _, obj := range objects {
+			require.NoError(t, p.Put(obj))
+		}
+	}
+}
+
+func TestCopyOnGet(t *testing.T) {
+	var copies, cleans atomic.Int64
+
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(1).
+		SetHardLimit(1).
+		SetMinShrinkCapacity(1).
+		SetFastPathInitialSize(1).
+		SetAllocationStrategy(100, 1).
+		SetCopyOnGet(func(obj *TestObject) *TestObject {
+			copies.Add(1)
+			dst := *obj
+			return &dst
+		}).
+		Build()
+	require.NoError(t, err)
+
+	allocator := func() *TestObject {
+		return &TestObject{Value: 42}
+	}
+
+	cleaner := func(obj *TestObject) {
+		cleans.Add(1)
+		obj.Value = 0
+	}
+
+	p, err := pool.NewPool(config, allocator, cleaner, nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	poolObj := p.(*pool.Pool[*TestObject])
+
+	first, err := p.Get()
+	require.NoError(t, err)
+	second, err := p.Get()
+	require.NoError(t, err, "the original stays in the pool, so a single object serves concurrent Gets")
+	assert.NotSame(t, first, second)
+	assert.Equal(t, 2, poolObj.InFlight())
+
+	first.Value = -1
+	second.Value = -2
+	require.NoError(t, p.Put(first))
+	require.NoError(t, p.Put(second))
+	assert.Equal(t, int64(2), cleans.Load(), "copies are cleaned when dropped")
+	assert.Equal(t, 0, poolObj.InFlight())
+
+	for range 3 {
+		obj, err := p.Get()
+		require.NoError(t, err)
+		assert.Equal(t, 42, obj.Value, "mutated copies never leak into the pool")
+		obj.Value = 7
+		require.NoError(t, p.Put(obj))
+	}
+
+	assert.Equal(t, int64(5), copies.Load())
+	assert.Equal(t, 1, poolObj.GetPoolStatsSnapshot().ObjectsCreated)
+
+	idle := 0
+	poolObj.Inspect(func(obj *TestObject) {
+		idle++
+		assert.Equal(t, 42, obj.Value)
+	})
+	assert.Equal(t, 1, idle)
+
+	t.Run("copies failing validation are dropped, not destroyed", func(t *testing.T) {
+		config, err := pool.NewPoolConfigBuilder[*TestObject]().
+			SetInitialCapacity(1).
+			SetHardLimit(1).
+			SetMinShrinkCapacity(1).
+			SetFastPathInitialSize(1).
+			SetAllocationStrategy(100, 1).
+			SetCopyOnGet(func(obj *TestObject) *TestObject {
+				dst := *obj
+				return &dst
+			}).
+			SetValidator(func(obj *TestObject) bool {
+				return obj.Value >= 0
+			}).
+			Build()
+		require.NoError(t, err)
+
+		p, err := pool.NewPool(config, allocator, cleaner, nil)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, p.Close())
+		}()
+
+		poolObj := p.(*pool.Pool[*TestObject])
+
+		for range 100 {
+			obj, err := p.Get()
+			require.NoError(t, err)
+			obj.Value = -1
+			require.NoError(t, p.Put(obj))
+		}
+
+		stats := poolObj.GetPoolStatsSnapshot()
+		assert.Equal(t, 1, stats.ObjectsCreated)
+		assert.Equal(t, 0, stats.ObjectsDestroyed, "the pool never created the copies")
+		assert.Equal(t, uint64(0), stats.RejectedPuts)
+		assert.Equal(t, 0, poolObj.InFlight())
+	})
+
+	t.Run("max object age is rejected", func(t *testing.T) {
+		_, err := pool.NewPoolConfigBuilder[*TestObject]().
+			SetCopyOnGet(func(obj *TestObject) *TestObject { return obj }).
+			SetMaxObjectAge(time.Minute).
+			Build()
+		assert.Error(t, err)
+	})
+}
+
+func TestAsyncClean(t *testing.T) {
+	started := make(chan *TestObject, 3)
+	gate := make(chan struct{})
+
+	config, err := pool.NewPoolConfigBuilder[*TestObject]().
+		SetInitialCapacity(3).
+		SetHardLimit(3).
+		SetMinShrinkCapacity(3).
+		SetFastPathInitialSize(3).
+		SetAllocationStrategy(100, 3).
+		SetAsyncClean(1).
+		Build()
+	require.NoError(t, err)
+
+	allocator := func() *TestObject {
+		return &TestObject{Value: 42}
+	}
+
+	cleaner := func(obj *TestObject) {
+		select {
+		case started <- obj:
+		default:
+		}
+		<-gate
+		obj.Value = 0
+	}
+
+	p, err := pool.NewPool(config, allocator, cleaner, nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	poolObj := p.(*pool.Pool[*TestObject])
+
+	objects := make([]*TestObject, 3)
+	for i := range objects {
+		objects[i], err = p.Get()
+		require.NoError(t, err)
+		objects[i].Value = i + 1
+	}
+
+	require.NoError(t, p.Put(objects[0]), "Put doesn't wait for the slow cleaner")
+	assert.Same(t, objects[0], <-started)
+
+	require.NoError(t, p.Put(objects[1]), "the queue has room for one more object")
+	assert.Equal(t, 3, poolObj.InFlight(), "queued objects count as in flight until they're back in the pool")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, p.Put(objects[2]))
+	}()
+
+	assert.Same(t, objects[2], <-started, "Put cleans synchronously once the queue is full")
+	select {
+	case <-done:
+		t.Fatal("Put returned before its synchronous clean finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(gate)
+	<-done
+
+	require.Eventually(t, func() bool {
+		return poolObj.InFlight() == 0
+	}, time.Second, 5*time.Millisecond)
+
+	for _, obj := range objects {
+		assert.Equal(t, 0, obj.Value, "every object was cleaned")
+	}
+
+	for range objects {
+		obj, err := p.Get()
+		require.NoError(t, err)
+		assert.True(t, slices.Contains(objects, obj), "cleaned objects are reused")
+		require.NoError(t, p.Put(obj))
+	}
+
+	require.Eventually(t, func() bool {
+		return poolObj.InFlight() == 0
+	}, time.Second, 5*time.Millisecond)
+}